@@ -1,7 +1,132 @@
 package main
 
-import "fmt"
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/ast"
+	"github.com/naoto0822/monkey-interpreter/pkg/evaluator"
+	"github.com/naoto0822/monkey-interpreter/pkg/lexer"
+	"github.com/naoto0822/monkey-interpreter/pkg/monkey"
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+	"github.com/naoto0822/monkey-interpreter/pkg/optimizer"
+	"github.com/naoto0822/monkey-interpreter/pkg/parser"
+)
+
+var (
+	optimize = flag.Bool("optimize", false, "fold constant expressions of program before evaluation")
+	dump     = flag.Bool("dump", false, "print program folded as -optimize instead of evaluating it")
+	seed     = flag.Int64("seed", 0, "seed of random builtins, random if not given")
+	fsRoot   = flag.String("fs", "", "enable file builtins under the directory")
+	fsWrite  = flag.Bool("fs-write", false, "allow file builtins to write under -fs directory")
+	maxDepth = flag.Int("max-depth", 0, "limit depth of nested function calls, 0 is unlimited")
+	timeout  = flag.Duration("timeout", 0, "limit evaluation time, 0 is unlimited")
+	path     = flag.String("path", "", "list of directories to search modules, separated by "+string(filepath.ListSeparator))
+)
 
 func main() {
-	fmt.Println("monkey!")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: interpreter [flags] <file>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *dump {
+		l := lexer.New(string(src))
+		p := parser.New(l)
+		program := p.ParseProgram()
+
+		if len(p.Errors()) > 0 {
+			for _, msg := range p.Errors() {
+				fmt.Fprintln(os.Stderr, msg)
+			}
+			os.Exit(1)
+		}
+
+		// macros are expanded as Run does before optimization
+		env := object.NewEnvironment()
+		if err := evaluator.DefineMacros(program, env); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		expanded, err := evaluator.ExpandMacros(program, env)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		// same pass as WithOptimize, so that dump shows what runs
+		fmt.Println(optimizer.Fold(expanded.(*ast.Program)).String())
+		return
+	}
+
+	opts := []monkey.Option{
+		monkey.WithOutput(os.Stdout),
+		monkey.WithInput(os.Stdin),
+		monkey.WithMaxCallDepth(*maxDepth),
+		monkey.WithTimeout(*timeout),
+		monkey.WithImportPath(importPath()...),
+	}
+
+	if *optimize {
+		opts = append(opts, monkey.WithOptimize())
+	}
+
+	if *fsRoot != "" {
+		opts = append(opts, monkey.WithFileSystem(&evaluator.FileSystem{
+			Root:  *fsRoot,
+			Read:  true,
+			Write: *fsWrite,
+		}))
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts = append(opts, monkey.WithSeed(*seed))
+		}
+	})
+
+	evaluated, err := monkey.New(opts...).Run(string(src))
+	if err != nil {
+		if perr, ok := err.(*monkey.ParseError); ok {
+			for _, msg := range perr.Errors {
+				fmt.Fprintln(os.Stderr, msg)
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+
+	// final value is printed unless it is NULL such as result of puts
+	if evaluated != nil && evaluated.Type() != object.NULL_OBJ {
+		fmt.Println(evaluated.Inspect())
+	}
+}
+
+// importPath return directory of script followed by -path directories
+func importPath() []string {
+	dirs := []string{filepath.Dir(flag.Arg(0))}
+
+	if *path != "" {
+		dirs = append(dirs, strings.Split(*path, string(filepath.ListSeparator))...)
+	}
+
+	return dirs
 }
@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"path"
 	"strings"
 
 	"github.com/naoto0822/monkey-interpreter/pkg/token"
@@ -465,6 +466,35 @@ func (i *IndexExpression) String() string {
 	return out.String()
 }
 
+var _ Expression = (*DotExpression)(nil)
+
+// DotExpression is exp.name, field of hash or method of value
+type DotExpression struct {
+	Token token.Token
+	Left  Expression
+	Name  *Identifier
+}
+
+func (d *DotExpression) expressionNode() {}
+
+// TokenLiteral implements Expression
+func (d *DotExpression) TokenLiteral() string {
+	return d.Token.Literal
+}
+
+// String implements Expression
+func (d *DotExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(d.Left.String())
+	out.WriteString(".")
+	out.WriteString(d.Name.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
 // HashLiteral is {k:v}
 type HashLiteral struct {
 	Token token.Token
@@ -494,3 +524,202 @@ func (h *HashLiteral) String() string {
 
 	return out.String()
 }
+
+var _ Statement = (*ThrowStatement)(nil)
+
+// ThrowStatement is throw expression;
+type ThrowStatement struct {
+	Token token.Token
+	Value Expression
+}
+
+func (s *ThrowStatement) statementNode() {}
+
+// TokenLiteral implements Statement
+func (s *ThrowStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+// String implements Statement
+func (s *ThrowStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(s.TokenLiteral() + " ")
+
+	if s.Value != nil {
+		out.WriteString(s.Value.String())
+	}
+
+	out.WriteString(";")
+	return out.String()
+}
+
+var _ Expression = (*TryExpression)(nil)
+
+// TryExpression is try { } catch (e) { } finally { }
+type TryExpression struct {
+	Token   token.Token
+	Block   *BlockStatement
+	Param   *Identifier
+	Catch   *BlockStatement
+	Finally *BlockStatement
+}
+
+func (t *TryExpression) expressionNode() {}
+
+// TokenLiteral implements Expression
+func (t *TryExpression) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+// String implements Expression
+func (t *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(t.Block.String())
+
+	if t.Catch != nil {
+		out.WriteString("catch(")
+		out.WriteString(t.Param.String())
+		out.WriteString(") ")
+		out.WriteString(t.Catch.String())
+	}
+
+	if t.Finally != nil {
+		out.WriteString("finally ")
+		out.WriteString(t.Finally.String())
+	}
+
+	return out.String()
+}
+
+var _ Statement = (*ImportStatement)(nil)
+
+// ImportStatement is import "path" as name;
+type ImportStatement struct {
+	Token token.Token
+	Path  string
+	// Alias is nil if as is omitted
+	Alias *Identifier
+}
+
+func (s *ImportStatement) statementNode() {}
+
+// TokenLiteral implements Statement
+func (s *ImportStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+// String implements Statement
+func (s *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(s.TokenLiteral() + " ")
+	out.WriteString("\"" + s.Path + "\"")
+
+	if s.Alias != nil {
+		out.WriteString(" as " + s.Alias.String())
+	}
+
+	out.WriteString(";")
+	return out.String()
+}
+
+// Name return name bound by import, Alias or file name w/o extension
+func (s *ImportStatement) Name() string {
+	if s.Alias != nil {
+		return s.Alias.Value
+	}
+
+	name := path.Base(s.Path)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+var _ Statement = (*ExportStatement)(nil)
+
+// ExportStatement is export let x = y; at top level of module
+type ExportStatement struct {
+	Token     token.Token
+	Statement *LetStatement
+}
+
+func (s *ExportStatement) statementNode() {}
+
+// TokenLiteral implements Statement
+func (s *ExportStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+// String implements Statement
+func (s *ExportStatement) String() string {
+	return s.TokenLiteral() + " " + s.Statement.String()
+}
+
+var _ Statement = (*StructStatement)(nil)
+
+// StructStatement is struct Name { field, field }
+type StructStatement struct {
+	Token  token.Token
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (s *StructStatement) statementNode() {}
+
+// TokenLiteral implements Statement
+func (s *StructStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+// String implements Statement
+func (s *StructStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, f := range s.Fields {
+		fields = append(fields, f.String())
+	}
+
+	out.WriteString(s.TokenLiteral() + " ")
+	out.WriteString(s.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+var _ Expression = (*MacroLiteral)(nil)
+
+// MacroLiteral is macro(x, y){ quote(x); }
+type MacroLiteral struct {
+	Token      token.Token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (m *MacroLiteral) expressionNode() {}
+
+// TokenLiteral implements Expression
+func (m *MacroLiteral) TokenLiteral() string {
+	return m.Token.Literal
+}
+
+// String implements Expression
+func (m *MacroLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString(m.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") ")
+	out.WriteString(m.Body.String())
+
+	return out.String()
+}
@@ -0,0 +1,147 @@
+package ast
+
+// ModifierFunc return node replacing given node
+type ModifierFunc func(Node) Node
+
+// Modify walk node depth-first and replace each node w/ result of modifier,
+// children are modified before their parent. node is not changed,
+// nodes on the way to modified one are copied.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch node := node.(type) {
+	case *Program:
+		n := *node
+		n.Statements = modifyStatements(node.Statements, modifier)
+		return modifier(&n)
+	case *ExpressionStatement:
+		n := *node
+		n.Expression = modifyExpression(node.Expression, modifier)
+		return modifier(&n)
+	case *BlockStatement:
+		n := *node
+		n.Statements = modifyStatements(node.Statements, modifier)
+		return modifier(&n)
+	case *ReturnStatement:
+		n := *node
+		n.ReturnValue = modifyExpression(node.ReturnValue, modifier)
+		return modifier(&n)
+	case *LetStatement:
+		n := *node
+		n.Value = modifyExpression(node.Value, modifier)
+		return modifier(&n)
+	case *ThrowStatement:
+		n := *node
+		n.Value = modifyExpression(node.Value, modifier)
+		return modifier(&n)
+	case *ExportStatement:
+		n := *node
+		n.Statement, _ = Modify(node.Statement, modifier).(*LetStatement)
+		return modifier(&n)
+	case *PrefixExpression:
+		n := *node
+		n.Right = modifyExpression(node.Right, modifier)
+		return modifier(&n)
+	case *InfixExpression:
+		n := *node
+		n.Left = modifyExpression(node.Left, modifier)
+		n.Right = modifyExpression(node.Right, modifier)
+		return modifier(&n)
+	case *IndexExpression:
+		n := *node
+		n.Left = modifyExpression(node.Left, modifier)
+		n.Index = modifyExpression(node.Index, modifier)
+		return modifier(&n)
+	case *DotExpression:
+		n := *node
+		n.Left = modifyExpression(node.Left, modifier)
+		return modifier(&n)
+	case *IfExpression:
+		n := *node
+		n.Condition = modifyExpression(node.Condition, modifier)
+		n.Consequence = modifyBlock(node.Consequence, modifier)
+		n.Alternative = modifyBlock(node.Alternative, modifier)
+		return modifier(&n)
+	case *FunctionLiteral:
+		n := *node
+		n.Parameters = modifyIdentifiers(node.Parameters, modifier)
+		n.Body = modifyBlock(node.Body, modifier)
+		return modifier(&n)
+	case *CallExpression:
+		n := *node
+		n.Function = modifyExpression(node.Function, modifier)
+		n.Arguments = modifyExpressions(node.Arguments, modifier)
+		return modifier(&n)
+	case *ArrayLiteral:
+		n := *node
+		n.Elements = modifyExpressions(node.Elements, modifier)
+		return modifier(&n)
+	case *HashLiteral:
+		n := *node
+		n.Pairs = make(map[Expression]Expression, len(node.Pairs))
+		for k, v := range node.Pairs {
+			n.Pairs[modifyExpression(k, modifier)] = modifyExpression(v, modifier)
+		}
+		return modifier(&n)
+	case *TryExpression:
+		n := *node
+		n.Block = modifyBlock(node.Block, modifier)
+		n.Catch = modifyBlock(node.Catch, modifier)
+		n.Finally = modifyBlock(node.Finally, modifier)
+		return modifier(&n)
+	}
+
+	return modifier(node)
+}
+
+func modifyStatements(stmts []Statement, modifier ModifierFunc) []Statement {
+	result := make([]Statement, 0, len(stmts))
+
+	for _, s := range stmts {
+		stmt, _ := Modify(s, modifier).(Statement)
+		result = append(result, stmt)
+	}
+
+	return result
+}
+
+func modifyExpression(exp Expression, modifier ModifierFunc) Expression {
+	if exp == nil {
+		return nil
+	}
+
+	result, _ := Modify(exp, modifier).(Expression)
+	return result
+}
+
+func modifyExpressions(exps []Expression, modifier ModifierFunc) []Expression {
+	result := make([]Expression, 0, len(exps))
+
+	for _, e := range exps {
+		result = append(result, modifyExpression(e, modifier))
+	}
+
+	return result
+}
+
+// modifyIdentifiers modify parameters, they are kept if replaced w/ other node
+func modifyIdentifiers(idents []*Identifier, modifier ModifierFunc) []*Identifier {
+	result := make([]*Identifier, 0, len(idents))
+
+	for _, i := range idents {
+		ident, ok := Modify(i, modifier).(*Identifier)
+		if !ok {
+			ident = i
+		}
+		result = append(result, ident)
+	}
+
+	return result
+}
+
+func modifyBlock(block *BlockStatement, modifier ModifierFunc) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+
+	result, _ := Modify(block, modifier).(*BlockStatement)
+	return result
+}
@@ -0,0 +1,125 @@
+package ast
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/token"
+)
+
+func TestModify(t *testing.T) {
+	one := func() Expression { return newTestInteger(1) }
+	two := func() Expression { return newTestInteger(2) }
+	block := func(e Expression) *BlockStatement {
+		return &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: e}}}
+	}
+
+	turnOneIntoTwo := func(node Node) Node {
+		integer, ok := node.(*IntegerLiteral)
+		if !ok || integer.Value != 1 {
+			return node
+		}
+
+		return newTestInteger(2)
+	}
+
+	tests := []struct {
+		input    Node
+		expected Node
+	}{
+		{one(), two()},
+		{
+			&Program{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			&Program{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+		},
+		{
+			&InfixExpression{Left: one(), Operator: "+", Right: one()},
+			&InfixExpression{Left: two(), Operator: "+", Right: two()},
+		},
+		{
+			&PrefixExpression{Operator: "-", Right: one()},
+			&PrefixExpression{Operator: "-", Right: two()},
+		},
+		{
+			&IndexExpression{Left: one(), Index: one()},
+			&IndexExpression{Left: two(), Index: two()},
+		},
+		{
+			&DotExpression{Left: one(), Name: &Identifier{Value: "x"}},
+			&DotExpression{Left: two(), Name: &Identifier{Value: "x"}},
+		},
+		{
+			&IfExpression{Condition: one(), Consequence: block(one()), Alternative: block(one())},
+			&IfExpression{Condition: two(), Consequence: block(two()), Alternative: block(two())},
+		},
+		{
+			&IfExpression{Condition: one(), Consequence: block(one())},
+			&IfExpression{Condition: two(), Consequence: block(two())},
+		},
+		{&ReturnStatement{ReturnValue: one()}, &ReturnStatement{ReturnValue: two()}},
+		{&ThrowStatement{Value: one()}, &ThrowStatement{Value: two()}},
+		{
+			&LetStatement{Name: &Identifier{Value: "x"}, Value: one()},
+			&LetStatement{Name: &Identifier{Value: "x"}, Value: two()},
+		},
+		{
+			&ExportStatement{Statement: &LetStatement{Name: &Identifier{Value: "x"}, Value: one()}},
+			&ExportStatement{Statement: &LetStatement{Name: &Identifier{Value: "x"}, Value: two()}},
+		},
+		{
+			&FunctionLiteral{Parameters: []*Identifier{}, Body: block(one())},
+			&FunctionLiteral{Parameters: []*Identifier{}, Body: block(two())},
+		},
+		{
+			&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{one(), one()}},
+			&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{two(), two()}},
+		},
+		{
+			&ArrayLiteral{Elements: []Expression{one(), one()}},
+			&ArrayLiteral{Elements: []Expression{two(), two()}},
+		},
+		{
+			&TryExpression{Block: block(one()), Param: &Identifier{Value: "e"}, Catch: block(one())},
+			&TryExpression{Block: block(two()), Param: &Identifier{Value: "e"}, Catch: block(two())},
+		},
+	}
+
+	for _, tt := range tests {
+		before := tt.input.String()
+		modified := Modify(tt.input, turnOneIntoTwo)
+
+		if modified.String() != tt.expected.String() {
+			t.Errorf("not modified. want=%s, got=%s", tt.expected.String(), modified.String())
+		}
+
+		if tt.input.String() != before {
+			t.Errorf("input is changed. want=%s, got=%s", before, tt.input.String())
+		}
+	}
+
+	hash := &HashLiteral{
+		Pairs: map[Expression]Expression{
+			one(): one(),
+		},
+	}
+
+	modified := Modify(hash, turnOneIntoTwo).(*HashLiteral)
+	for k, v := range modified.Pairs {
+		key, _ := k.(*IntegerLiteral)
+		if key.Value != 2 {
+			t.Errorf("key is not 2. got=%d", key.Value)
+		}
+
+		value, _ := v.(*IntegerLiteral)
+		if value.Value != 2 {
+			t.Errorf("value is not 2. got=%d", value.Value)
+		}
+	}
+}
+
+func newTestInteger(value int64) *IntegerLiteral {
+	return &IntegerLiteral{
+		Token: token.Token{Type: token.INT, Literal: fmt.Sprintf("%d", value)},
+		Value: value,
+	}
+}
@@ -6,9 +6,9 @@ import (
 
 var builtins = map[string]*object.Builtin{
 	"len": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
 			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1",
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
 					len(args))
 			}
 
@@ -24,20 +24,20 @@ var builtins = map[string]*object.Builtin{
 					Value: value,
 				}
 			default:
-				return newError("argument to `len` not supported, got %s",
+				return newTypeError("argument to `len` not supported, got %s",
 					args[0].Type())
 			}
 		},
 	},
 	"first": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
 			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1",
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
 					len(args))
 			}
 
 			if args[0].Type() != object.ARRAY_OBJ {
-				return newError("argument to `first` must be ARRAY, got %s", args[0].Type())
+				return newTypeError("argument to `first` must be ARRAY, got %s", args[0].Type())
 			}
 
 			arr := args[0].(*object.Array)
@@ -49,14 +49,14 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"last": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
 			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1",
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
 					len(args))
 			}
 
 			if args[0].Type() != object.ARRAY_OBJ {
-				return newError("argument to `last` must be ARRAY, got %s", args[0].Type())
+				return newTypeError("argument to `last` must be ARRAY, got %s", args[0].Type())
 			}
 
 			arr := args[0].(*object.Array)
@@ -67,15 +67,40 @@ var builtins = map[string]*object.Builtin{
 			return NULL
 		},
 	},
+	"rest": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `rest` must be ARRAY, got %s", args[0].Type())
+			}
+
+			arr := args[0].(*object.Array)
+			length := len(arr.Elements)
+			if length > 0 {
+				newElements := make([]object.Object, length-1, length-1)
+				copy(newElements, arr.Elements[1:length])
+
+				return &object.Array{
+					Elements: newElements,
+				}
+			}
+
+			return NULL
+		},
+	},
 	"push": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
 			if len(args) != 2 {
-				return newError("wrong number of arguments. got=%d, want=2",
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
 					len(args))
 			}
 
 			if args[0].Type() != object.ARRAY_OBJ {
-				return newError("argument to `push` must be ARRAY, got %s", args[0].Type())
+				return newTypeError("argument to `push` must be ARRAY, got %s", args[0].Type())
 			}
 
 			arr := args[0].(*object.Array)
@@ -91,3 +116,35 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 }
+
+// registerBuiltins add bs to builtins.
+// Builtins are grouped by file and registered in init,
+// which also avoids initialization cycle of builtins calling applyFunction.
+func registerBuiltins(bs map[string]*object.Builtin) {
+	for name, b := range bs {
+		builtins[name] = b
+	}
+}
+
+// Register bind builtin to name in env, so that it is visible only from env
+// and Envs enclosed by it. builtin of same name is shadowed only in them,
+// builtins of other environments are not changed. it panics if env is frozen.
+func Register(env *object.Environment, name string, b *object.Builtin) {
+	env.Set(name, b)
+}
+
+// RegisterFunc wrap Go func fn by Wrap and Register it on env
+func RegisterFunc(env *object.Environment, name string, fn interface{}) error {
+	b, err := Wrap(fn)
+	if err != nil {
+		return err
+	}
+
+	Register(env, name, b)
+	return nil
+}
+
+func lookupBuiltin(name string) (*object.Builtin, bool) {
+	b, ok := builtins[name]
+	return b, ok
+}
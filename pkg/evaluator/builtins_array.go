@@ -0,0 +1,367 @@
+package evaluator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(arrayBuiltins)
+}
+
+var arrayBuiltins = map[string]*object.Builtin{
+	"map": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `map` must be ARRAY, got %s", args[0].Type())
+			}
+
+			if !isCallable(args[1]) {
+				return newTypeError("argument to `map` must be FUNCTION, got %s", args[1].Type())
+			}
+
+			arr := args[0].(*object.Array)
+			newElements := make([]object.Object, len(arr.Elements), len(arr.Elements))
+
+			for i, e := range arr.Elements {
+				result := applyFunction(args[1], []object.Object{e}, env)
+				if isError(result) {
+					return result
+				}
+
+				newElements[i] = result
+			}
+
+			return &object.Array{
+				Elements: newElements,
+			}
+		},
+	},
+	"filter": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `filter` must be ARRAY, got %s", args[0].Type())
+			}
+
+			if !isCallable(args[1]) {
+				return newTypeError("argument to `filter` must be FUNCTION, got %s", args[1].Type())
+			}
+
+			arr := args[0].(*object.Array)
+			newElements := []object.Object{}
+
+			for _, e := range arr.Elements {
+				result := applyFunction(args[1], []object.Object{e}, env)
+				if isError(result) {
+					return result
+				}
+
+				if isTruthly(result) {
+					newElements = append(newElements, e)
+				}
+			}
+
+			return &object.Array{
+				Elements: newElements,
+			}
+		},
+	},
+	// reduce(arr, fn(acc, e), initial), initial is first element if omitted
+	"reduce": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2 or 3",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `reduce` must be ARRAY, got %s", args[0].Type())
+			}
+
+			if !isCallable(args[1]) {
+				return newTypeError("argument to `reduce` must be FUNCTION, got %s", args[1].Type())
+			}
+
+			elements := args[0].(*object.Array).Elements
+
+			var acc object.Object
+			if len(args) == 3 {
+				acc = args[2]
+			} else {
+				if len(elements) == 0 {
+					return NULL
+				}
+
+				acc = elements[0]
+				elements = elements[1:]
+			}
+
+			for _, e := range elements {
+				acc = applyFunction(args[1], []object.Object{acc, e}, env)
+				if isError(acc) {
+					return acc
+				}
+			}
+
+			return acc
+		},
+	},
+	// sort(arr, fn(a, b)), fn returns INTEGER like Compare or BOOLEAN as a < b
+	"sort": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `sort` must be ARRAY, got %s", args[0].Type())
+			}
+
+			if len(args) == 2 && !isCallable(args[1]) {
+				return newTypeError("argument to `sort` must be FUNCTION, got %s", args[1].Type())
+			}
+
+			arr := args[0].(*object.Array)
+			newElements := make([]object.Object, len(arr.Elements), len(arr.Elements))
+			copy(newElements, arr.Elements)
+
+			var err object.Object
+			sort.SliceStable(newElements, func(i, j int) bool {
+				if err != nil {
+					return false
+				}
+
+				a, b := newElements[i], newElements[j]
+
+				if len(args) == 1 {
+					result, ok := object.Compare(a, b)
+					if !ok {
+						err = newTypeError("unable to compare %s and %s", a.Type(), b.Type())
+					}
+
+					return result < 0
+				}
+
+				less, lessErr := applyComparator(args[1], a, b, env)
+				if lessErr != nil {
+					err = lessErr
+				}
+
+				return less
+			})
+
+			if err != nil {
+				return err
+			}
+
+			return &object.Array{
+				Elements: newElements,
+			}
+		},
+	},
+	"reverse": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `reverse` must be ARRAY, got %s", args[0].Type())
+			}
+
+			arr := args[0].(*object.Array)
+			length := len(arr.Elements)
+			newElements := make([]object.Object, length, length)
+
+			for i, e := range arr.Elements {
+				newElements[length-1-i] = e
+			}
+
+			return &object.Array{
+				Elements: newElements,
+			}
+		},
+	},
+	"contains": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Array:
+				return nativeBoolToBooleanObject(indexOf(arg, args[1]) >= 0)
+			case *object.String:
+				sub, ok := args[1].(*object.String)
+				if !ok {
+					return newTypeError("argument to `contains` must be STRING, got %s", args[1].Type())
+				}
+
+				return nativeBoolToBooleanObject(strings.Contains(arg.Value, sub.Value))
+			default:
+				return newTypeError("argument to `contains` not supported, got %s",
+					args[0].Type())
+			}
+		},
+	},
+	"index_of": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `index_of` must be ARRAY, got %s", args[0].Type())
+			}
+
+			return &object.Integer{
+				Value: int64(indexOf(args[0].(*object.Array), args[1])),
+			}
+		},
+	},
+	"concat": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			newElements := []object.Object{}
+
+			for _, arg := range args {
+				if arg.Type() != object.ARRAY_OBJ {
+					return newTypeError("argument to `concat` must be ARRAY, got %s", arg.Type())
+				}
+
+				newElements = append(newElements, arg.(*object.Array).Elements...)
+			}
+
+			return &object.Array{
+				Elements: newElements,
+			}
+		},
+	},
+	// flatten(arr, depth), nested arrays are flattened completely if depth is omitted
+	"flatten": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `flatten` must be ARRAY, got %s", args[0].Type())
+			}
+
+			depth := int64(-1)
+			if len(args) == 2 {
+				if args[1].Type() != object.INTEGER_OBJ {
+					return newTypeError("argument to `flatten` must be INTEGER, got %s", args[1].Type())
+				}
+
+				depth = args[1].(*object.Integer).Value
+			}
+
+			return &object.Array{
+				Elements: flatten(args[0].(*object.Array).Elements, depth),
+			}
+		},
+	},
+	"zip": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return newArgumentError("wrong number of arguments. got=0, want>=1")
+			}
+
+			length := -1
+			for _, arg := range args {
+				if arg.Type() != object.ARRAY_OBJ {
+					return newTypeError("argument to `zip` must be ARRAY, got %s", arg.Type())
+				}
+
+				if l := len(arg.(*object.Array).Elements); length < 0 || l < length {
+					length = l
+				}
+			}
+
+			newElements := make([]object.Object, length, length)
+			for i := range newElements {
+				tuple := make([]object.Object, len(args), len(args))
+				for j, arg := range args {
+					tuple[j] = arg.(*object.Array).Elements[i]
+				}
+
+				newElements[i] = &object.Array{
+					Elements: tuple,
+				}
+			}
+
+			return &object.Array{
+				Elements: newElements,
+			}
+		},
+	},
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin:
+		return true
+	default:
+		return false
+	}
+}
+
+// applyComparator call comparator fn of sort and report whether a < b
+func applyComparator(fn, a, b object.Object, env *object.Environment) (bool, object.Object) {
+	result := applyFunction(fn, []object.Object{a, b}, env)
+
+	switch result := result.(type) {
+	case *object.Integer:
+		return result.Value < 0, nil
+	case *object.Boolean:
+		return result.Value, nil
+	case *object.Error:
+		return false, result
+	default:
+		return false, newTypeError("comparator of `sort` must return INTEGER or BOOLEAN, got %s",
+			result.Type())
+	}
+}
+
+func indexOf(arr *object.Array, obj object.Object) int {
+	for i, e := range arr.Elements {
+		if object.Equal(e, obj) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func flatten(elements []object.Object, depth int64) []object.Object {
+	result := []object.Object{}
+
+	for _, e := range elements {
+		nested, ok := e.(*object.Array)
+		if !ok || depth == 0 {
+			result = append(result, e)
+			continue
+		}
+
+		result = append(result, flatten(nested.Elements, depth-1)...)
+	}
+
+	return result
+}
@@ -0,0 +1,233 @@
+package evaluator
+
+import (
+	"reflect"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(concurrencyBuiltins)
+}
+
+// maxChannelCapacity is maximum capacity of channel, buffer is allocated
+// when channel is made
+const maxChannelCapacity = 1 << 20
+
+// concurrencyBuiltins block on Go channels, and return error instead of
+// blocking forever when all tasks of the script are blocked
+var concurrencyBuiltins = map[string]*object.Builtin{
+	// spawn(fn, args...) call fn on goroutine and return TASK
+	"spawn": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return newArgumentError("wrong number of arguments. got=0, want>=1")
+			}
+
+			if !isCallable(args[0]) {
+				return newTypeError("argument to `spawn` must be FUNCTION, got %s", args[0].Type())
+			}
+
+			s, err := currentScheduler(env, "spawn")
+			if err != nil {
+				return err
+			}
+
+			fn, fnArgs := args[0], args[1:]
+			task := &object.Task{
+				Done: make(chan struct{}),
+			}
+
+			// task keeps scheduler even if env gets one of next evaluation
+			taskEnv := object.NewEnclosedEnvironment(env)
+			taskEnv.SetValue(schedulerKey{}, s)
+
+			s.spawned()
+			go func() {
+				defer close(task.Done)
+				defer s.finished()
+				defer func() {
+					if r := recover(); r != nil {
+						task.Result = newError("spawn: panic: %v", r)
+					}
+				}()
+
+				task.Result = unwrapReturnValue(applyFunction(fn, fnArgs, taskEnv))
+			}()
+
+			return task
+		},
+	},
+	// await(task) return result of task, error of task is raised
+	"await": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			task, ok := args[0].(*object.Task)
+			if !ok {
+				return newTypeError("argument to `await` must be TASK, got %s", args[0].Type())
+			}
+
+			_, _, _, err := wait(env, "await", []reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(task.Done)},
+			})
+			if err != nil {
+				return err
+			}
+
+			// result is shared by every await of task
+			if err, ok := task.Result.(*object.Error); ok {
+				return err.Copy()
+			}
+
+			return task.Result
+		},
+	},
+	// channel(capacity), channel is unbuffered if capacity is omitted
+	"channel": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=0 or 1",
+					len(args))
+			}
+
+			capacity := int64(0)
+			if len(args) == 1 {
+				if err := checkIntegerArguments("channel", args...); err != nil {
+					return err
+				}
+
+				capacity = args[0].(*object.Integer).Value
+				if capacity < 0 {
+					return newArgumentError("argument to `channel` must not be negative, got %d", capacity)
+				}
+
+				if capacity > maxChannelCapacity {
+					return newArgumentError("argument to `channel` must not exceed %d, got %d",
+						maxChannelCapacity, capacity)
+				}
+			}
+
+			return &object.Channel{
+				Value: make(chan object.Object, capacity),
+			}
+		},
+	},
+	"send": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			ch, ok := args[0].(*object.Channel)
+			if !ok {
+				return newTypeError("argument to `send` must be CHANNEL, got %s", args[0].Type())
+			}
+
+			_, _, _, err := wait(env, "send", []reflect.SelectCase{
+				{Dir: reflect.SelectSend, Chan: reflect.ValueOf(ch.Value), Send: reflect.ValueOf(&args[1]).Elem()},
+			})
+			if err != nil {
+				return err
+			}
+
+			return NULL
+		},
+	},
+	// recv(ch) return NULL if ch is closed
+	"recv": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			ch, ok := args[0].(*object.Channel)
+			if !ok {
+				return newTypeError("argument to `recv` must be CHANNEL, got %s", args[0].Type())
+			}
+
+			_, value, ok, err := wait(env, "recv", []reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch.Value)},
+			})
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return NULL
+			}
+
+			return value.Interface().(object.Object)
+		},
+	},
+	"close": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) (result object.Object) {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			ch, ok := args[0].(*object.Channel)
+			if !ok {
+				return newTypeError("argument to `close` must be CHANNEL, got %s", args[0].Type())
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					result = newError("close: %v", r)
+				}
+			}()
+
+			close(ch.Value)
+			return NULL
+		},
+	},
+	// select(channels) receive from whichever is ready first,
+	// and return [index, value], value is NULL if the channel is closed
+	"select": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newTypeError("argument to `select` must be ARRAY, got %s", args[0].Type())
+			}
+
+			if len(arr.Elements) == 0 {
+				return newArgumentError("argument to `select` must not be empty")
+			}
+
+			cases := make([]reflect.SelectCase, len(arr.Elements), len(arr.Elements))
+			for i, e := range arr.Elements {
+				ch, ok := e.(*object.Channel)
+				if !ok {
+					return newTypeError("argument to `select` must be ARRAY of CHANNEL, got %s", e.Type())
+				}
+
+				cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch.Value)}
+			}
+
+			chosen, value, ok, err := wait(env, "select", cases)
+			if err != nil {
+				return err
+			}
+
+			var received object.Object = NULL
+			if ok {
+				received = value.Interface().(object.Object)
+			}
+
+			return &object.Array{
+				Elements: []object.Object{&object.Integer{Value: int64(chosen)}, received},
+			}
+		},
+	},
+}
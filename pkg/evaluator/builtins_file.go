@@ -0,0 +1,174 @@
+package evaluator
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(fileBuiltins)
+}
+
+// fileBuiltins work only under FileSystem set by SetFileSystem
+var fileBuiltins = map[string]*object.Builtin{
+	"read_file": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			path, err := filePath(env, "read_file", args[0], false)
+			if err != nil {
+				return err
+			}
+
+			data, readErr := ioutil.ReadFile(path)
+			if readErr != nil {
+				return newFileError(args[0], readErr)
+			}
+
+			return &object.String{
+				Value: string(data),
+			}
+		},
+	},
+	// write_file(path, s) create or truncate file
+	"write_file": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return writeFile(env, "write_file", os.O_TRUNC, args)
+		},
+	},
+	// append_file(path, s) create file if not exists
+	"append_file": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return writeFile(env, "append_file", os.O_APPEND, args)
+		},
+	},
+	// list_dir(path) return sorted names, root is listed if path is omitted
+	"list_dir": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=0 or 1",
+					len(args))
+			}
+
+			var dir object.Object = &object.String{Value: "."}
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			path, err := filePath(env, "list_dir", dir, false)
+			if err != nil {
+				return err
+			}
+
+			infos, readErr := ioutil.ReadDir(path)
+			if readErr != nil {
+				return newFileError(dir, readErr)
+			}
+
+			names := make([]string, len(infos), len(infos))
+			for i, info := range infos {
+				names[i] = info.Name()
+			}
+			sort.Strings(names)
+
+			return newStringArray(names)
+		},
+	},
+	"exists": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			path, err := filePath(env, "exists", args[0], false)
+			if err != nil {
+				return err
+			}
+
+			_, statErr := os.Stat(path)
+			if os.IsNotExist(statErr) {
+				return FALSE
+			}
+			if statErr != nil {
+				return newFileError(args[0], statErr)
+			}
+
+			return TRUE
+		},
+	},
+}
+
+// filePath check permission of FileSystem and return host path of arg
+func filePath(env *object.Environment, name string, arg object.Object, write bool) (string, *object.Error) {
+	if err := checkStringArguments(name, arg); err != nil {
+		return "", err
+	}
+
+	fs := fileSystem(env)
+	if fs == nil {
+		return "", newError("%s: file system access is disabled", name)
+	}
+
+	if write && !fs.Write {
+		return "", newError("%s: file system is not writable", name)
+	}
+
+	if !write && !fs.Read {
+		return "", newError("%s: file system is not readable", name)
+	}
+
+	path := arg.(*object.String).Value
+	resolved, err := fs.resolve(path)
+	if err != nil {
+		return "", newError("%s: %s: %s", name, path, err)
+	}
+
+	return resolved, nil
+}
+
+// newFileError report err with path seen by script instead of host path
+func newFileError(path object.Object, err error) *object.Error {
+	if pathErr, ok := err.(*os.PathError); ok {
+		pathErr.Path = path.(*object.String).Value
+	}
+
+	return newError("%s", err)
+}
+
+func writeFile(env *object.Environment, name string, flag int, args []object.Object) object.Object {
+	if len(args) != 2 {
+		return newArgumentError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+
+	path, err := filePath(env, name, args[0], true)
+	if err != nil {
+		return err
+	}
+
+	if err := checkStringArguments(name, args[1]); err != nil {
+		return err
+	}
+
+	f, openErr := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|flag, 0644)
+	if openErr != nil {
+		return newFileError(args[0], openErr)
+	}
+
+	_, writeErr := f.WriteString(args[1].(*object.String).Value)
+	if closeErr := f.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		return newFileError(args[0], writeErr)
+	}
+
+	return NULL
+}
@@ -0,0 +1,188 @@
+package evaluator
+
+import (
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(hashBuiltins)
+}
+
+// hashBuiltins never modify given hash, and return keys in the order
+// of object.Hash.SortedPairs.
+var hashBuiltins = map[string]*object.Builtin{
+	"keys": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return newTypeError("argument to `keys` must be HASH, got %s", args[0].Type())
+			}
+
+			elements := []object.Object{}
+			for _, pair := range args[0].(*object.Hash).SortedPairs() {
+				elements = append(elements, pair.Key)
+			}
+
+			return &object.Array{
+				Elements: elements,
+			}
+		},
+	},
+	"values": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return newTypeError("argument to `values` must be HASH, got %s", args[0].Type())
+			}
+
+			elements := []object.Object{}
+			for _, pair := range args[0].(*object.Hash).SortedPairs() {
+				elements = append(elements, pair.Value)
+			}
+
+			return &object.Array{
+				Elements: elements,
+			}
+		},
+	},
+	// items(h) return [[key, value], ...]
+	"items": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return newTypeError("argument to `items` must be HASH, got %s", args[0].Type())
+			}
+
+			elements := []object.Object{}
+			for _, pair := range args[0].(*object.Hash).SortedPairs() {
+				elements = append(elements, &object.Array{
+					Elements: []object.Object{pair.Key, pair.Value},
+				})
+			}
+
+			return &object.Array{
+				Elements: elements,
+			}
+		},
+	},
+	"has": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return newTypeError("argument to `has` must be HASH, got %s", args[0].Type())
+			}
+
+			if _, ok := object.HashKeyOf(args[1]); !ok {
+				return newTypeError("unusable as hash key: %s", args[1].Type())
+			}
+
+			_, ok := args[0].(*object.Hash).Get(args[1])
+			return nativeBoolToBooleanObject(ok)
+		},
+	},
+	// get(h, key, default), default is NULL if omitted
+	"get": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2 or 3",
+					len(args))
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return newTypeError("argument to `get` must be HASH, got %s", args[0].Type())
+			}
+
+			if value, ok := args[0].(*object.Hash).Get(args[1]); ok {
+				return value
+			}
+
+			if _, ok := object.HashKeyOf(args[1]); !ok {
+				return newTypeError("unusable as hash key: %s", args[1].Type())
+			}
+
+			if len(args) == 3 {
+				return args[2]
+			}
+
+			return NULL
+		},
+	},
+	// set(h, key, value) return new hash
+	"set": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 3 {
+				return newArgumentError("wrong number of arguments. got=%d, want=3",
+					len(args))
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return newTypeError("argument to `set` must be HASH, got %s", args[0].Type())
+			}
+
+			hash := args[0].(*object.Hash).Copy()
+			if !hash.Set(args[1], args[2]) {
+				return newTypeError("unusable as hash key: %s", args[1].Type())
+			}
+
+			return hash
+		},
+	},
+	// delete(h, key) return new hash
+	"delete": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return newTypeError("argument to `delete` must be HASH, got %s", args[0].Type())
+			}
+
+			if _, ok := object.HashKeyOf(args[1]); !ok {
+				return newTypeError("unusable as hash key: %s", args[1].Type())
+			}
+
+			hash := args[0].(*object.Hash).Copy()
+			hash.Delete(args[1])
+
+			return hash
+		},
+	},
+	// merge(h1, h2, ...) return new hash, later value wins
+	"merge": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			hash := object.NewHash()
+
+			for _, arg := range args {
+				if arg.Type() != object.HASH_OBJ {
+					return newTypeError("argument to `merge` must be HASH, got %s", arg.Type())
+				}
+
+				for _, bucket := range arg.(*object.Hash).Pairs {
+					for _, pair := range bucket {
+						hash.Set(pair.Key, pair.Value)
+					}
+				}
+			}
+
+			return hash
+		},
+	},
+}
@@ -0,0 +1,71 @@
+package evaluator
+
+import (
+	"io"
+	"strings"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(ioBuiltins)
+}
+
+var ioBuiltins = map[string]*object.Builtin{
+	// puts(args...) write each argument on its own line
+	"puts": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			// write once so that output of tasks is not interleaved
+			var out strings.Builder
+			for _, arg := range args {
+				out.WriteString(arg.Inspect() + "\n")
+			}
+
+			if _, err := io.WriteString(output(env), out.String()); err != nil {
+				return newError("puts: %s", err)
+			}
+
+			return NULL
+		},
+	},
+	// print(args...) write arguments without separator and newline
+	"print": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			var out strings.Builder
+			for _, arg := range args {
+				out.WriteString(arg.Inspect())
+			}
+
+			if _, err := io.WriteString(output(env), out.String()); err != nil {
+				return newError("print: %s", err)
+			}
+
+			return NULL
+		},
+	},
+	// read_line() return line without newline, NULL at end of input
+	"read_line": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newArgumentError("wrong number of arguments. got=%d, want=0",
+					len(args))
+			}
+
+			line, err := input(env).ReadString('\n')
+			if err == io.EOF && line == "" {
+				return NULL
+			}
+
+			if err != nil && err != io.EOF {
+				return newError("read_line: %s", err)
+			}
+
+			line = strings.TrimSuffix(line, "\n")
+			line = strings.TrimSuffix(line, "\r")
+
+			return &object.String{
+				Value: line,
+			}
+		},
+	},
+}
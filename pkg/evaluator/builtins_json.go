@@ -0,0 +1,81 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(jsonBuiltins)
+}
+
+// maxIndent is maximum length of indent of json_stringify like JSON.stringify,
+// indent is repeated for each level of nesting
+const maxIndent = 10
+
+var jsonBuiltins = map[string]*object.Builtin{
+	"json_parse": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if err := checkStringArguments("json_parse", args...); err != nil {
+				return err
+			}
+
+			obj, err := object.FromJSON([]byte(args[0].(*object.String).Value))
+			if err != nil {
+				return newValueError("%s", err)
+			}
+
+			return obj
+		},
+	},
+	// json_stringify(obj, indent), indent is INTEGER of spaces or STRING,
+	// output is compact if indent is omitted
+	"json_stringify": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+					len(args))
+			}
+
+			indent := ""
+			if len(args) == 2 {
+				switch arg := args[1].(type) {
+				case *object.Integer:
+					if arg.Value < 0 {
+						return newArgumentError("argument to `json_stringify` must not be negative, got %d",
+							arg.Value)
+					}
+					if arg.Value > maxIndent {
+						return newArgumentError("argument to `json_stringify` must not exceed %d, got %d",
+							maxIndent, arg.Value)
+					}
+					indent = strings.Repeat(" ", int(arg.Value))
+				case *object.String:
+					if len(arg.Value) > maxIndent {
+						return newArgumentError("argument to `json_stringify` must not be longer than %d, got %d",
+							maxIndent, len(arg.Value))
+					}
+					indent = arg.Value
+				default:
+					return newTypeError("argument to `json_stringify` must be INTEGER or STRING, got %s",
+						args[1].Type())
+				}
+			}
+
+			data, err := object.ToJSON(args[0], indent)
+			if err != nil {
+				return newTypeError("%s", err)
+			}
+
+			return &object.String{
+				Value: string(data),
+			}
+		},
+	},
+}
@@ -0,0 +1,401 @@
+package evaluator
+
+import (
+	"math"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(mathBuiltins)
+}
+
+// mathBuiltins operate on INTEGER only since Monkey has no float.
+var mathBuiltins = map[string]*object.Builtin{
+	"abs": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if err := checkIntegerArguments("abs", args...); err != nil {
+				return err
+			}
+
+			value := args[0].(*object.Integer).Value
+			if value == math.MinInt64 {
+				return newValueError("abs(%d) overflows INTEGER", value)
+			}
+
+			if value < 0 {
+				return &object.Integer{Value: -value}
+			}
+
+			return args[0]
+		},
+	},
+	// min(a, b, ...) or min(arr)
+	"min": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return extremum("min", args, func(a, b int64) bool { return a < b })
+		},
+	},
+	// max(a, b, ...) or max(arr)
+	"max": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return extremum("max", args, func(a, b int64) bool { return a > b })
+		},
+	},
+	// sum(arr), sum of empty array is 0
+	"sum": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `sum` must be ARRAY, got %s", args[0].Type())
+			}
+
+			elements := args[0].(*object.Array).Elements
+			if err := checkIntegerArguments("sum", elements...); err != nil {
+				return err
+			}
+
+			var total int64
+			for i, e := range elements {
+				var ok bool
+				if total, ok = add(total, e.(*object.Integer).Value); !ok {
+					return newValueError("sum overflows INTEGER at [%d]", i)
+				}
+			}
+
+			return &object.Integer{
+				Value: total,
+			}
+		},
+	},
+	"pow": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if err := checkIntegerArguments("pow", args...); err != nil {
+				return err
+			}
+
+			base := args[0].(*object.Integer).Value
+			exp := args[1].(*object.Integer).Value
+			if exp < 0 {
+				return newArgumentError("argument to `pow` must not be negative, got %d", exp)
+			}
+
+			result, square, n := int64(1), base, exp
+			for n > 0 {
+				var ok bool
+				if n&1 == 1 {
+					if result, ok = multiply(result, square); !ok {
+						return newValueError("pow(%d, %d) overflows INTEGER", base, exp)
+					}
+				}
+
+				n >>= 1
+				if n == 0 {
+					break
+				}
+
+				if square, ok = multiply(square, square); !ok {
+					return newValueError("pow(%d, %d) overflows INTEGER", base, exp)
+				}
+			}
+
+			return &object.Integer{
+				Value: result,
+			}
+		},
+	},
+	// sqrt(x) return floor of square root
+	"sqrt": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if err := checkIntegerArguments("sqrt", args...); err != nil {
+				return err
+			}
+
+			value := args[0].(*object.Integer).Value
+			if value < 0 {
+				return newValueError("square root of negative number %d", value)
+			}
+
+			root := int64(math.Sqrt(float64(value)))
+			// float64 loses precision for large value
+			for root*root > value {
+				root--
+			}
+			for (root+1)*(root+1) <= value && (root+1)*(root+1) > 0 {
+				root++
+			}
+
+			return &object.Integer{
+				Value: root,
+			}
+		},
+	},
+	// floor(x, d) return floor of x / d, x is returned as is if d is omitted
+	"floor": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return divideRounding("floor", args, func(r, d int64) bool {
+				return r != 0 && (r < 0) != (d < 0)
+			}, func(r, d int64) bool {
+				return false
+			})
+		},
+	},
+	// ceil(x, d) return ceil of x / d, x is returned as is if d is omitted
+	"ceil": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return divideRounding("ceil", args, func(r, d int64) bool {
+				return false
+			}, func(r, d int64) bool {
+				return r != 0 && (r < 0) == (d < 0)
+			})
+		},
+	},
+	// round(x, d) return x / d rounded half away from zero,
+	// x is returned as is if d is omitted
+	"round": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return divideRounding("round", args, func(r, d int64) bool {
+				return (r < 0) != (d < 0) && r != 0 && !isLessThanHalf(r, d)
+			}, func(r, d int64) bool {
+				return (r < 0) == (d < 0) && r != 0 && !isLessThanHalf(r, d)
+			})
+		},
+	},
+	"gcd": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if err := checkIntegerArguments("gcd", args...); err != nil {
+				return err
+			}
+
+			a := args[0].(*object.Integer).Value
+			b := args[1].(*object.Integer).Value
+			x, y := a, b
+			for y != 0 {
+				x, y = y, x%y
+			}
+			if x == math.MinInt64 {
+				return newValueError("gcd(%d, %d) overflows INTEGER", a, b)
+			}
+			if x < 0 {
+				x = -x
+			}
+
+			return &object.Integer{
+				Value: x,
+			}
+		},
+	},
+	// clamp(x, lo, hi)
+	"clamp": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 3 {
+				return newArgumentError("wrong number of arguments. got=%d, want=3",
+					len(args))
+			}
+
+			if err := checkIntegerArguments("clamp", args...); err != nil {
+				return err
+			}
+
+			value := args[0].(*object.Integer).Value
+			lo := args[1].(*object.Integer).Value
+			hi := args[2].(*object.Integer).Value
+			if lo > hi {
+				return newArgumentError("argument to `clamp` has lo > hi, got %d > %d", lo, hi)
+			}
+
+			switch {
+			case value < lo:
+				return args[1]
+			case value > hi:
+				return args[2]
+			default:
+				return args[0]
+			}
+		},
+	},
+	// range(end), range(start, end) or range(start, end, step), end is exclusive
+	"range": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 3 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 to 3",
+					len(args))
+			}
+
+			if err := checkIntegerArguments("range", args...); err != nil {
+				return err
+			}
+
+			start, end, step := int64(0), int64(0), int64(1)
+			switch len(args) {
+			case 1:
+				end = args[0].(*object.Integer).Value
+			case 2:
+				start = args[0].(*object.Integer).Value
+				end = args[1].(*object.Integer).Value
+			case 3:
+				start = args[0].(*object.Integer).Value
+				end = args[1].(*object.Integer).Value
+				step = args[2].(*object.Integer).Value
+			}
+
+			if step == 0 {
+				return newArgumentError("argument to `range` must not be zero step")
+			}
+
+			count := rangeLength(start, end, step)
+			if count > maxLength {
+				return newArgumentError("range has %d elements, must not exceed %d", count, maxLength)
+			}
+
+			elements := make([]object.Object, count)
+			for i := range elements {
+				// wraps in uint64 but each element is between start and end
+				value := uint64(start) + uint64(i)*uint64(step)
+				elements[i] = &object.Integer{Value: int64(value)}
+			}
+
+			return &object.Array{
+				Elements: elements,
+			}
+		},
+	},
+}
+
+// checkIntegerArguments return error if any of args is not INTEGER
+func checkIntegerArguments(name string, args ...object.Object) *object.Error {
+	for _, arg := range args {
+		if arg.Type() != object.INTEGER_OBJ {
+			return newTypeError("argument to `%s` must be INTEGER, got %s", name, arg.Type())
+		}
+	}
+
+	return nil
+}
+
+// add return a + b, and false if it overflows
+func add(a, b int64) (int64, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+// multiply return a * b, and false if it overflows
+func multiply(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	c := a * b
+	return c, c/b == a
+}
+
+// rangeLength return number of elements of range w/o overflow, step is not 0
+func rangeLength(start, end, step int64) uint64 {
+	switch {
+	case step > 0 && start < end:
+		return (uint64(end)-uint64(start)-1)/uint64(step) + 1
+	case step < 0 && start > end:
+		// -step overflows if step is MinInt64
+		return (uint64(start)-uint64(end)-1)/(uint64(-(step+1))+1) + 1
+	default:
+		return 0
+	}
+}
+
+// extremum return the element which wins over all others by better
+func extremum(name string, args []object.Object, better func(a, b int64) bool) object.Object {
+	if len(args) == 1 && args[0].Type() == object.ARRAY_OBJ {
+		args = args[0].(*object.Array).Elements
+	}
+
+	if len(args) == 0 {
+		return newArgumentError("argument to `%s` must not be empty", name)
+	}
+
+	if err := checkIntegerArguments(name, args...); err != nil {
+		return err
+	}
+
+	result := args[0]
+	for _, arg := range args[1:] {
+		if better(arg.(*object.Integer).Value, result.(*object.Integer).Value) {
+			result = arg
+		}
+	}
+
+	return result
+}
+
+// divideRounding divide x by d, and adjust truncated quotient by down or up
+func divideRounding(name string, args []object.Object, down, up func(r, d int64) bool) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+
+	if err := checkIntegerArguments(name, args...); err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		return args[0]
+	}
+
+	x := args[0].(*object.Integer).Value
+	d := args[1].(*object.Integer).Value
+	if d == 0 {
+		return newError("division by zero")
+	}
+
+	q, r := x/d, x%d
+	switch {
+	case down(r, d):
+		q--
+	case up(r, d):
+		q++
+	}
+
+	return &object.Integer{
+		Value: q,
+	}
+}
+
+// isLessThanHalf report whether |r| < |d| / 2
+func isLessThanHalf(r, d int64) bool {
+	if r < 0 {
+		r = -r
+	}
+	if d < 0 {
+		d = -d
+	}
+
+	return r < d-r
+}
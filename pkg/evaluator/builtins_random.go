@@ -0,0 +1,98 @@
+package evaluator
+
+import (
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(randomBuiltins)
+}
+
+// randomBuiltins draw from generator set by SetRand or SetSeed
+var randomBuiltins = map[string]*object.Builtin{
+	// random() return non-negative INTEGER
+	"random": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newArgumentError("wrong number of arguments. got=%d, want=0",
+					len(args))
+			}
+
+			return &object.Integer{
+				Value: random(env).Int63(),
+			}
+		},
+	},
+	// random_int(lo, hi) return INTEGER in lo..hi, both inclusive
+	"random_int": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if err := checkIntegerArguments("random_int", args...); err != nil {
+				return err
+			}
+
+			lo := args[0].(*object.Integer).Value
+			hi := args[1].(*object.Integer).Value
+			if lo > hi {
+				return newArgumentError("argument to `random_int` has lo > hi, got %d > %d", lo, hi)
+			}
+
+			span := hi - lo + 1
+			if span <= 0 {
+				return newArgumentError("argument to `random_int` has too large range")
+			}
+
+			return &object.Integer{
+				Value: lo + random(env).Int63n(span),
+			}
+		},
+	},
+	// shuffle(arr) return new shuffled array
+	"shuffle": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `shuffle` must be ARRAY, got %s", args[0].Type())
+			}
+
+			arr := args[0].(*object.Array)
+			newElements := make([]object.Object, len(arr.Elements), len(arr.Elements))
+			copy(newElements, arr.Elements)
+
+			random(env).Shuffle(len(newElements), func(i, j int) {
+				newElements[i], newElements[j] = newElements[j], newElements[i]
+			})
+
+			return &object.Array{
+				Elements: newElements,
+			}
+		},
+	},
+	"choice": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `choice` must be ARRAY, got %s", args[0].Type())
+			}
+
+			elements := args[0].(*object.Array).Elements
+			if len(elements) == 0 {
+				return newArgumentError("argument to `choice` must not be empty")
+			}
+
+			return elements[random(env).Intn(len(elements))]
+		},
+	},
+}
@@ -0,0 +1,192 @@
+package evaluator
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(regexBuiltins)
+}
+
+// regexBuiltins accept pattern as REGEX or STRING, STRING is compiled through regexCache
+var regexBuiltins = map[string]*object.Builtin{
+	// regex(pattern) return compiled REGEX
+	"regex": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			re, err := regexArgument("regex", args[0])
+			if err != nil {
+				return err
+			}
+
+			return &object.Regex{
+				Value: re,
+			}
+		},
+	},
+	// match(pattern, s) return [whole, groups...] of first match, NULL if not matched
+	"match": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			re, err := regexArgument("match", args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := checkStringArguments("match", args[1]); err != nil {
+				return err
+			}
+
+			matched := re.FindStringSubmatch(args[1].(*object.String).Value)
+			if matched == nil {
+				return NULL
+			}
+
+			return newStringArray(matched)
+		},
+	},
+	// find_all(pattern, s, n), all matches are returned if n is omitted
+	"find_all": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2 or 3",
+					len(args))
+			}
+
+			re, err := regexArgument("find_all", args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := checkStringArguments("find_all", args[1]); err != nil {
+				return err
+			}
+
+			n := int64(-1)
+			if len(args) == 3 {
+				if err := checkIntegerArguments("find_all", args[2]); err != nil {
+					return err
+				}
+
+				n = args[2].(*object.Integer).Value
+			}
+
+			return newStringArray(re.FindAllString(args[1].(*object.String).Value, int(n)))
+		},
+	},
+	// replace_re(pattern, s, repl), repl can refer group by $1 or ${name}
+	"replace_re": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 3 {
+				return newArgumentError("wrong number of arguments. got=%d, want=3",
+					len(args))
+			}
+
+			re, err := regexArgument("replace_re", args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := checkStringArguments("replace_re", args[1:]...); err != nil {
+				return err
+			}
+
+			return &object.String{
+				Value: re.ReplaceAllString(
+					args[1].(*object.String).Value,
+					args[2].(*object.String).Value,
+				),
+			}
+		},
+	},
+	// split_re(pattern, s, n), s is split around all matches if n is omitted
+	"split_re": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2 or 3",
+					len(args))
+			}
+
+			re, err := regexArgument("split_re", args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := checkStringArguments("split_re", args[1]); err != nil {
+				return err
+			}
+
+			n := int64(-1)
+			if len(args) == 3 {
+				if err := checkIntegerArguments("split_re", args[2]); err != nil {
+					return err
+				}
+
+				n = args[2].(*object.Integer).Value
+			}
+
+			return newStringArray(re.Split(args[1].(*object.String).Value, int(n)))
+		},
+	},
+}
+
+// regexCacheSize is max number of patterns kept by regexCache
+const regexCacheSize = 128
+
+// regexCache keep patterns compiled from STRING, it is reset when full
+var regexCache = struct {
+	sync.Mutex
+	patterns map[string]*regexp.Regexp
+}{
+	patterns: map[string]*regexp.Regexp{},
+}
+
+// compileRegex compile pattern through regexCache
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.Lock()
+	defer regexCache.Unlock()
+
+	if re, ok := regexCache.patterns[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(regexCache.patterns) >= regexCacheSize {
+		regexCache.patterns = map[string]*regexp.Regexp{}
+	}
+	regexCache.patterns[pattern] = re
+
+	return re, nil
+}
+
+// regexArgument return regexp of REGEX or compiled STRING
+func regexArgument(name string, arg object.Object) (*regexp.Regexp, *object.Error) {
+	switch arg := arg.(type) {
+	case *object.Regex:
+		return arg.Value, nil
+	case *object.String:
+		re, err := compileRegex(arg.Value)
+		if err != nil {
+			return nil, newValueError("invalid pattern to `%s`: %s", name, err)
+		}
+
+		return re, nil
+	default:
+		return nil, newTypeError("argument to `%s` must be REGEX or STRING, got %s", name, arg.Type())
+	}
+}
@@ -0,0 +1,389 @@
+package evaluator
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(stringBuiltins)
+}
+
+var stringBuiltins = map[string]*object.Builtin{
+	// split(s, sep), s is split around whitespace if sep is omitted
+	"split": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+					len(args))
+			}
+
+			if err := checkStringArguments("split", args...); err != nil {
+				return err
+			}
+
+			s := args[0].(*object.String).Value
+
+			var fields []string
+			if len(args) == 1 {
+				fields = strings.Fields(s)
+			} else {
+				fields = strings.Split(s, args[1].(*object.String).Value)
+			}
+
+			return newStringArray(fields)
+		},
+	},
+	// join(arr, sep), elements are joined by Inspect
+	"join": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+					len(args))
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return newTypeError("argument to `join` must be ARRAY, got %s", args[0].Type())
+			}
+
+			sep := ""
+			if len(args) == 2 {
+				if err := checkStringArguments("join", args[1]); err != nil {
+					return err
+				}
+
+				sep = args[1].(*object.String).Value
+			}
+
+			elements := []string{}
+			for _, e := range args[0].(*object.Array).Elements {
+				elements = append(elements, e.Inspect())
+			}
+
+			return &object.String{
+				Value: strings.Join(elements, sep),
+			}
+		},
+	},
+	// trim(s, cutset), whitespace is trimmed if cutset is omitted
+	"trim": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+					len(args))
+			}
+
+			if err := checkStringArguments("trim", args...); err != nil {
+				return err
+			}
+
+			s := args[0].(*object.String).Value
+			if len(args) == 1 {
+				return &object.String{Value: strings.TrimSpace(s)}
+			}
+
+			return &object.String{
+				Value: strings.Trim(s, args[1].(*object.String).Value),
+			}
+		},
+	},
+	"upper": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if err := checkStringArguments("upper", args...); err != nil {
+				return err
+			}
+
+			return &object.String{
+				Value: strings.ToUpper(args[0].(*object.String).Value),
+			}
+		},
+	},
+	"lower": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if err := checkStringArguments("lower", args...); err != nil {
+				return err
+			}
+
+			return &object.String{
+				Value: strings.ToLower(args[0].(*object.String).Value),
+			}
+		},
+	},
+	// replace(s, old, new, n), all old are replaced if n is omitted
+	"replace": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 3 && len(args) != 4 {
+				return newArgumentError("wrong number of arguments. got=%d, want=3 or 4",
+					len(args))
+			}
+
+			if err := checkStringArguments("replace", args[:3]...); err != nil {
+				return err
+			}
+
+			n := int64(-1)
+			if len(args) == 4 {
+				if args[3].Type() != object.INTEGER_OBJ {
+					return newTypeError("argument to `replace` must be INTEGER, got %s", args[3].Type())
+				}
+
+				n = args[3].(*object.Integer).Value
+			}
+
+			return &object.String{
+				Value: strings.Replace(
+					args[0].(*object.String).Value,
+					args[1].(*object.String).Value,
+					args[2].(*object.String).Value,
+					int(n),
+				),
+			}
+		},
+	},
+	"starts_with": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if err := checkStringArguments("starts_with", args...); err != nil {
+				return err
+			}
+
+			return nativeBoolToBooleanObject(strings.HasPrefix(
+				args[0].(*object.String).Value,
+				args[1].(*object.String).Value,
+			))
+		},
+	},
+	"ends_with": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if err := checkStringArguments("ends_with", args...); err != nil {
+				return err
+			}
+
+			return nativeBoolToBooleanObject(strings.HasSuffix(
+				args[0].(*object.String).Value,
+				args[1].(*object.String).Value,
+			))
+		},
+	},
+	"repeat": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			if err := checkStringArguments("repeat", args[0]); err != nil {
+				return err
+			}
+
+			if args[1].Type() != object.INTEGER_OBJ {
+				return newTypeError("argument to `repeat` must be INTEGER, got %s", args[1].Type())
+			}
+
+			count := args[1].(*object.Integer).Value
+			if count < 0 {
+				return newArgumentError("argument to `repeat` must not be negative, got %d", count)
+			}
+
+			s := args[0].(*object.String).Value
+			if s != "" && count > int64(maxLength/len(s)) {
+				return newArgumentError("result of `repeat` must not exceed %d bytes, got %d * %d",
+					maxLength, len(s), count)
+			}
+
+			return &object.String{
+				Value: strings.Repeat(s, int(count)),
+			}
+		},
+	},
+	"chars": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if err := checkStringArguments("chars", args...); err != nil {
+				return err
+			}
+
+			chars := []string{}
+			for _, r := range args[0].(*object.String).Value {
+				chars = append(chars, string(r))
+			}
+
+			return newStringArray(chars)
+		},
+	},
+	// pad_left(s, width, pad), s is padded with space if pad is omitted
+	"pad_left": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return pad("pad_left", true, args)
+		},
+	},
+	// pad_right(s, width, pad), s is padded with space if pad is omitted
+	"pad_right": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return pad("pad_right", false, args)
+		},
+	},
+	// format(f, args...), f accepts %d for INTEGER, %s for STRING, %v for any and %%
+	"format": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return newArgumentError("wrong number of arguments. got=0, want>=1")
+			}
+
+			if err := checkStringArguments("format", args[0]); err != nil {
+				return err
+			}
+
+			return format(args[0].(*object.String).Value, args[1:])
+		},
+	},
+}
+
+// checkStringArguments return error if any of args is not STRING
+func checkStringArguments(name string, args ...object.Object) *object.Error {
+	for _, arg := range args {
+		if arg.Type() != object.STRING_OBJ {
+			return newTypeError("argument to `%s` must be STRING, got %s", name, arg.Type())
+		}
+	}
+
+	return nil
+}
+
+func newStringArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values), len(values))
+	for i, v := range values {
+		elements[i] = &object.String{Value: v}
+	}
+
+	return &object.Array{
+		Elements: elements,
+	}
+}
+
+func pad(name string, left bool, args []object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newArgumentError("wrong number of arguments. got=%d, want=2 or 3",
+			len(args))
+	}
+
+	if err := checkStringArguments(name, args[0]); err != nil {
+		return err
+	}
+
+	if args[1].Type() != object.INTEGER_OBJ {
+		return newTypeError("argument to `%s` must be INTEGER, got %s", name, args[1].Type())
+	}
+
+	padding := " "
+	if len(args) == 3 {
+		if err := checkStringArguments(name, args[2]); err != nil {
+			return err
+		}
+
+		padding = args[2].(*object.String).Value
+		if padding == "" {
+			return newArgumentError("argument to `%s` must not be empty", name)
+		}
+	}
+
+	width := args[1].(*object.Integer).Value
+	if width > maxLength {
+		return newArgumentError("argument to `%s` must not exceed %d, got %d", name, maxLength, width)
+	}
+
+	s := args[0].(*object.String).Value
+	missing := int(width) - utf8.RuneCountInString(s)
+	if missing <= 0 {
+		return args[0]
+	}
+
+	// repeat padding only as many times as needed to fill
+	times := missing/utf8.RuneCountInString(padding) + 1
+	fill := []rune(strings.Repeat(padding, times))[:missing]
+	if left {
+		return &object.String{Value: string(fill) + s}
+	}
+
+	return &object.String{Value: s + string(fill)}
+}
+
+func format(f string, args []object.Object) object.Object {
+	var out bytes.Buffer
+	next := 0
+
+	for i := 0; i < len(f); i++ {
+		if f[i] != '%' {
+			out.WriteByte(f[i])
+			continue
+		}
+
+		i++
+		if i >= len(f) {
+			return newArgumentError("format: missing verb at end of %q", f)
+		}
+
+		verb := f[i]
+		if verb == '%' {
+			out.WriteByte('%')
+			continue
+		}
+
+		if next >= len(args) {
+			return newArgumentError("format: missing argument for %%%c", verb)
+		}
+
+		arg := args[next]
+		next++
+
+		switch verb {
+		case 'd':
+			if arg.Type() != object.INTEGER_OBJ {
+				return newTypeError("format: %%d must be INTEGER, got %s", arg.Type())
+			}
+		case 's':
+			if arg.Type() != object.STRING_OBJ {
+				return newTypeError("format: %%s must be STRING, got %s", arg.Type())
+			}
+		case 'v':
+		default:
+			return newArgumentError("format: unknown verb %%%c", verb)
+		}
+
+		out.WriteString(arg.Inspect())
+	}
+
+	if next < len(args) {
+		return newArgumentError("format: too many arguments. got=%d, want=%d", len(args), next)
+	}
+
+	return &object.String{
+		Value: out.String(),
+	}
+}
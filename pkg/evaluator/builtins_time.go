@@ -0,0 +1,115 @@
+package evaluator
+
+import (
+	"time"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(timeBuiltins)
+}
+
+// timeBuiltins take layout of Go time package, RFC3339 if layout is omitted
+var timeBuiltins = map[string]*object.Builtin{
+	// now() return TIME of clock set by SetClock
+	"now": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newArgumentError("wrong number of arguments. got=%d, want=0",
+					len(args))
+			}
+
+			return &object.Time{
+				Value: clock(env)(),
+			}
+		},
+	},
+	// time_parse(s, layout)
+	"time_parse": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+					len(args))
+			}
+
+			if err := checkStringArguments("time_parse", args...); err != nil {
+				return err
+			}
+
+			layout := time.RFC3339
+			if len(args) == 2 {
+				layout = args[1].(*object.String).Value
+			}
+
+			t, err := time.Parse(layout, args[0].(*object.String).Value)
+			if err != nil {
+				return newValueError("%s", err)
+			}
+
+			return &object.Time{
+				Value: t,
+			}
+		},
+	},
+	// time_format(t, layout)
+	"time_format": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1 or 2",
+					len(args))
+			}
+
+			if args[0].Type() != object.TIME_OBJ {
+				return newTypeError("argument to `time_format` must be TIME, got %s", args[0].Type())
+			}
+
+			layout := time.RFC3339
+			if len(args) == 2 {
+				if err := checkStringArguments("time_format", args[1]); err != nil {
+					return err
+				}
+
+				layout = args[1].(*object.String).Value
+			}
+
+			return &object.String{
+				Value: args[0].(*object.Time).Value.Format(layout),
+			}
+		},
+	},
+	// time_unix(t) return seconds since Unix epoch
+	"time_unix": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() != object.TIME_OBJ {
+				return newTypeError("argument to `time_unix` must be TIME, got %s", args[0].Type())
+			}
+
+			return &object.Integer{
+				Value: args[0].(*object.Time).Value.Unix(),
+			}
+		},
+	},
+	// time_from_unix(seconds) return TIME in UTC
+	"time_from_unix": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if err := checkIntegerArguments("time_from_unix", args...); err != nil {
+				return err
+			}
+
+			return &object.Time{
+				Value: time.Unix(args[0].(*object.Integer).Value, 0).UTC(),
+			}
+		},
+	},
+}
@@ -0,0 +1,120 @@
+package evaluator
+
+import (
+	"strconv"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func init() {
+	registerBuiltins(typeBuiltins)
+}
+
+var typeBuiltins = map[string]*object.Builtin{
+	// type(x) return type name such as INTEGER
+	"type": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			// instance is typed by name of its struct
+			if s, ok := args[0].(*object.Struct); ok {
+				return &object.String{
+					Value: s.StructType.Name,
+				}
+			}
+
+			return &object.String{
+				Value: string(args[0].Type()),
+			}
+		},
+	},
+	"str": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			if args[0].Type() == object.STRING_OBJ {
+				return args[0]
+			}
+
+			return &object.String{
+				Value: args[0].Inspect(),
+			}
+		},
+	},
+	// int(x) parse STRING as decimal integer, BOOLEAN is 1 or 0
+	"int": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Integer:
+				return arg
+			case *object.String:
+				value, err := strconv.ParseInt(arg.Value, 10, 64)
+				if err != nil {
+					return newValueError("could not parse %q as integer", arg.Value)
+				}
+
+				return &object.Integer{
+					Value: value,
+				}
+			case *object.Boolean:
+				if arg.Value {
+					return &object.Integer{Value: 1}
+				}
+
+				return &object.Integer{Value: 0}
+			default:
+				return newTypeError("argument to `int` not supported, got %s",
+					args[0].Type())
+			}
+		},
+	},
+	// bool(x) follow truthiness of if
+	"bool": &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			return nativeBoolToBooleanObject(isTruthly(args[0]))
+		},
+	},
+	"is_int":      newTypePredicate(object.INTEGER_OBJ),
+	"is_string":   newTypePredicate(object.STRING_OBJ),
+	"is_bool":     newTypePredicate(object.BOOLEAN_OBJ),
+	"is_array":    newTypePredicate(object.ARRAY_OBJ),
+	"is_hash":     newTypePredicate(object.HASH_OBJ),
+	"is_null":     newTypePredicate(object.NULL_OBJ),
+	"is_function": newTypePredicate(object.FUNCTION_OBJ, object.BUILTIN_OBJ),
+}
+
+// newTypePredicate return builtin reporting whether argument is one of types
+func newTypePredicate(types ...object.Type) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			for _, t := range types {
+				if args[0].Type() == t {
+					return TRUE
+				}
+			}
+
+			return FALSE
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package evaluator
+
+import (
+	"time"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+type clockKey struct{}
+
+// SetClock set clock of now on env, tests can give fixed time
+func SetClock(env *object.Environment, clock func() time.Time) {
+	env.SetValue(clockKey{}, clock)
+}
+
+// clock return clock set by SetClock, time.Now if not set
+func clock(env *object.Environment) func() time.Time {
+	if c, ok := env.Value(clockKey{}).(func() time.Time); ok {
+		return c
+	}
+
+	return time.Now
+}
@@ -2,15 +2,16 @@ package evaluator
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/naoto0822/monkey-interpreter/pkg/ast"
 	"github.com/naoto0822/monkey-interpreter/pkg/object"
 )
 
 var (
-	NULL  = &object.Null{}
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
+	NULL  = object.NULL
+	TRUE  = object.TRUE
+	FALSE = object.FALSE
 )
 
 // Eval start parsing ast.Node
@@ -36,7 +37,24 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return value
 		}
 
+		if env.IsFrozen() {
+			return newError("cannot bind %s in frozen environment", node.Name.Value)
+		}
+
 		env.Set(node.Name.Value, value)
+	case *ast.ThrowStatement:
+		value := Eval(node.Value, env)
+		if isError(value) {
+			return value
+		}
+
+		return newThrownError(value)
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+	case *ast.ExportStatement:
+		return Eval(node.Statement, env)
+	case *ast.StructStatement:
+		return evalStructStatement(node, env)
 	// expression
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
@@ -78,7 +96,18 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			Body:       body,
 			Env:        env,
 		}
+	case *ast.MacroLiteral:
+		return newError("macro must be defined by top-level let")
 	case *ast.CallExpression:
+		if isCallOf(node, "quote") {
+			if len(node.Arguments) != 1 {
+				return newArgumentError("wrong number of arguments. got=%d, want=1",
+					len(node.Arguments))
+			}
+
+			return quote(node.Arguments[0], env)
+		}
+
 		function := Eval(node.Function, env)
 		if isError(function) {
 			return function
@@ -89,7 +118,15 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return args[0]
 		}
 
-		return applyFunction(function, args)
+		result := applyFunction(function, args, env)
+		if err, ok := result.(*object.Error); ok {
+			// err may be shared, such as result of task
+			err = err.Copy()
+			err.Stack = append(err.Stack, node.Function.String())
+			return err
+		}
+
+		return result
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
@@ -111,17 +148,34 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 
 		return evalIndexExpression(left, index)
+	case *ast.DotExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+
+		return evalDotExpression(left, node.Name.Value)
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
 	}
 
 	return nil
 }
 
 func evalProgram(program *ast.Program, env *object.Environment) object.Object {
+	env, finish := startScheduler(env)
+	defer finish()
+
+	return evalStatements(program.Statements, env)
+}
+
+// evalStatements evaluate statements of program or module
+func evalStatements(stmts []ast.Statement, env *object.Environment) object.Object {
 	var result object.Object
 
-	for _, stmt := range program.Statements {
+	for _, stmt := range stmts {
 		result = Eval(stmt, env)
 
 		switch result := result.(type) {
@@ -136,8 +190,29 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 }
 
 func newError(format string, a ...interface{}) *object.Error {
+	return newKindError(object.RUNTIME_ERROR, format, a...)
+}
+
+func newTypeError(format string, a ...interface{}) *object.Error {
+	return newKindError(object.TYPE_ERROR, format, a...)
+}
+
+func newNameError(format string, a ...interface{}) *object.Error {
+	return newKindError(object.NAME_ERROR, format, a...)
+}
+
+func newArgumentError(format string, a ...interface{}) *object.Error {
+	return newKindError(object.ARGUMENT_ERROR, format, a...)
+}
+
+func newValueError(format string, a ...interface{}) *object.Error {
+	return newKindError(object.VALUE_ERROR, format, a...)
+}
+
+func newKindError(kind string, format string, a ...interface{}) *object.Error {
 	return &object.Error{
 		Message: fmt.Sprintf(format, a...),
+		Kind:    kind,
 	}
 }
 
@@ -189,7 +264,7 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 	case "-":
 		return evalMinusPrefixOperatorExpression(right)
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newTypeError("unknown operator: %s%s", operator, right.Type())
 	}
 }
 
@@ -208,7 +283,7 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 	if right.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: -%s", right.Type())
+		return newTypeError("unknown operator: -%s", right.Type())
 	}
 
 	value := right.(*object.Integer).Value
@@ -223,14 +298,16 @@ func evalInfixExpression(operator string, left object.Object, right object.Objec
 		return evalIntegerInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
+	case left.Type() == object.TIME_OBJ || right.Type() == object.TIME_OBJ:
+		return evalTimeInfixExpression(operator, left, right)
 	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
+		return nativeBoolToBooleanObject(object.Equal(left, right))
 	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
+		return nativeBoolToBooleanObject(!object.Equal(left, right))
 	case left.Type() != right.Type():
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -252,9 +329,19 @@ func evalIntegerInfixExpression(operator string, left object.Object, right objec
 			Value: leftValue * rightValue,
 		}
 	case "/":
+		if rightValue == 0 {
+			return newError("division by zero")
+		}
 		return &object.Integer{
 			Value: leftValue / rightValue,
 		}
+	case "%":
+		if rightValue == 0 {
+			return newError("division by zero")
+		}
+		return &object.Integer{
+			Value: leftValue % rightValue,
+		}
 	case "<":
 		return nativeBoolToBooleanObject(leftValue < rightValue)
 	case ">":
@@ -264,8 +351,61 @@ func evalIntegerInfixExpression(operator string, left object.Object, right objec
 	case "!=":
 		return nativeBoolToBooleanObject(leftValue != rightValue)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalTimeInfixExpression treat INTEGER as seconds,
+// TIME - TIME return INTEGER of seconds
+func evalTimeInfixExpression(operator string, left, right object.Object) object.Object {
+	switch l := left.(type) {
+	case *object.Time:
+		switch r := right.(type) {
+		case *object.Time:
+			switch operator {
+			case "-":
+				return &object.Integer{
+					Value: int64(l.Value.Sub(r.Value) / time.Second),
+				}
+			case "<":
+				return nativeBoolToBooleanObject(l.Value.Before(r.Value))
+			case ">":
+				return nativeBoolToBooleanObject(l.Value.After(r.Value))
+			case "==":
+				return nativeBoolToBooleanObject(l.Value.Equal(r.Value))
+			case "!=":
+				return nativeBoolToBooleanObject(!l.Value.Equal(r.Value))
+			}
+		case *object.Integer:
+			switch operator {
+			case "+":
+				return &object.Time{
+					Value: l.Value.Add(time.Duration(r.Value) * time.Second),
+				}
+			case "-":
+				return &object.Time{
+					Value: l.Value.Add(-time.Duration(r.Value) * time.Second),
+				}
+			}
+		}
+	case *object.Integer:
+		if operator == "+" {
+			return evalTimeInfixExpression(operator, right, left)
+		}
+	}
+
+	switch operator {
+	case "==":
+		return nativeBoolToBooleanObject(object.Equal(left, right))
+	case "!=":
+		return nativeBoolToBooleanObject(!object.Equal(left, right))
+	}
+
+	if left.Type() != right.Type() {
+		return newTypeError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	}
+
+	return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 }
 
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
@@ -277,8 +417,16 @@ func evalStringInfixExpression(operator string, left, right object.Object) objec
 		return &object.String{
 			Value: leftValue + rightValue,
 		}
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -326,15 +474,24 @@ func isTruthly(obj object.Object) bool {
 }
 
 func evalIdentifier(ident *ast.Identifier, env *object.Environment) object.Object {
-	if value, ok := env.Get(ident.Value); ok {
+	if value, ok := Lookup(env, ident.Value); ok {
 		return value
 	}
 
-	if value, ok := builtins[ident.Value]; ok {
-		return value
+	return newNameError("identifier not found: %s", ident.Value)
+}
+
+// Lookup resolve name as identifier, env first and then builtins
+func Lookup(env *object.Environment, name string) (object.Object, bool) {
+	if value, ok := env.Get(name); ok {
+		return value, true
 	}
 
-	return newError("identifier not found: %s", ident.Value)
+	if value, ok := lookupBuiltin(name); ok {
+		return value, true
+	}
+
+	return nil, false
 }
 
 func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
@@ -352,21 +509,51 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return results
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+// applyFunction call fn with args, env is environment of the caller
+func applyFunction(fn object.Object, args []object.Object, env *object.Environment) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendEnv := extendFunctionEnv(fn, args)
+		if len(args) < len(fn.Parameters) {
+			return newArgumentError("wrong number of arguments. got=%d, want=%d",
+				len(args), len(fn.Parameters))
+		}
+
+		if err := checkLimits(env); err != nil {
+			return err
+		}
+
+		extendEnv := extendFunctionEnv(fn, args, env)
 		evaluated := Eval(fn.Body, extendEnv)
 		return unwrapReturnValue(evaluated)
 	case *object.Builtin:
-		return fn.Fn(args...)
+		return fn.Fn(env, args...)
+	case *object.StructType:
+		if len(args) != len(fn.Fields) {
+			return newArgumentError("wrong number of arguments. got=%d, want=%d",
+				len(args), len(fn.Fields))
+		}
+
+		return &object.Struct{
+			StructType: fn,
+			Values:     args,
+		}
 	default:
-		return newError("not a function: %s", fn.Type())
+		return newTypeError("not a function: %s", fn.Type())
 	}
 }
 
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
-	env := object.NewEnclosedEnvironment(fn.Env)
+// Apply call fn with args, env is environment of the caller
+func Apply(fn object.Object, args []object.Object, env *object.Environment) object.Object {
+	env, finish := startScheduler(env)
+	defer finish()
+
+	return unwrapReturnValue(applyFunction(fn, args, env))
+}
+
+// extendFunctionEnv gen Env of fn, host values such as output writer
+// are inherited from caller rather than where fn is defined
+func extendFunctionEnv(fn *object.Function, args []object.Object, caller *object.Environment) *object.Environment {
+	env := object.NewFunctionEnvironment(fn.Env, caller)
 
 	for i, p := range fn.Parameters {
 		env.Set(p.Value, args[i])
@@ -380,6 +567,11 @@ func unwrapReturnValue(obj object.Object) object.Object {
 		return rv.Value
 	}
 
+	// function with empty body has no value
+	if obj == nil {
+		return NULL
+	}
+
 	return obj
 }
 
@@ -389,26 +581,27 @@ func evalIndexExpression(left, index object.Object) object.Object {
 		return evalArrayIndexExpression(left, index)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
+	case left.Type() == object.MODULE_OBJ:
+		return evalModuleIndexExpression(left, index)
+	case left.Type() == object.STRUCT_OBJ:
+		return evalStructIndexExpression(left, index)
 	default:
-		return newError("index operator not supported: %s", left.Type())
+		return newTypeError("index operator not supported: %s", left.Type())
 	}
 }
 
 func evalHashIndexExpression(left, index object.Object) object.Object {
 	hash := left.(*object.Hash)
 
-	hashable, ok := index.(object.Hashable)
-	if !ok {
-		return newError("unusable as hash key: %s", index.Type())
+	if value, ok := hash.Get(index); ok {
+		return value
 	}
 
-	key := hashable.HashKey()
-	pair, ok := hash.Pairs[key]
-	if !ok {
-		return NULL
+	if _, ok := object.HashKeyOf(index); !ok {
+		return newTypeError("unusable as hash key: %s", index.Type())
 	}
 
-	return pair.Value
+	return NULL
 }
 
 func evalArrayIndexExpression(left, index object.Object) object.Object {
@@ -424,7 +617,7 @@ func evalArrayIndexExpression(left, index object.Object) object.Object {
 }
 
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
 	for k, v := range node.Pairs {
 		key := Eval(k, env)
@@ -432,24 +625,116 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 			return key
 		}
 
-		hashKey, ok := key.(object.Hashable)
-		if !ok {
-			return newError("unusable as hash key: %s", key.Type())
-		}
-
 		value := Eval(v, env)
 		if isError(value) {
 			return value
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{
-			Key:   key,
-			Value: value,
+		if !hash.Set(key, value) {
+			return newTypeError("unusable as hash key: %s", key.Type())
+		}
+	}
+
+	return hash
+}
+
+func evalTryExpression(node *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(node.Block, env)
+
+	// LimitError is not caught, so that script can not ignore limits of host
+	if err, ok := result.(*object.Error); ok && err.Kind != object.LIMIT_ERROR && node.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		catchEnv.Set(node.Param.Value, errorToHash(err))
+		result = Eval(node.Catch, catchEnv)
+	}
+
+	if node.Finally != nil {
+		finally := Eval(node.Finally, env)
+
+		if finally != nil {
+			rt := finally.Type()
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+				return finally
+			}
+		}
+	}
+
+	if result == nil {
+		return NULL
+	}
+
+	return result
+}
+
+// newThrownError convert thrown value to Error.
+// hash is read as caught error, so that catch block can rethrow it.
+func newThrownError(value object.Object) *object.Error {
+	err := &object.Error{
+		Message: value.Inspect(),
+		Kind:    object.THROWN_ERROR,
+		Value:   value,
+	}
+
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return err
+	}
+
+	if message, ok := hashField(hash, "message").(*object.String); ok {
+		err.Message = message.Value
+	}
+
+	if kind, ok := hashField(hash, "kind").(*object.String); ok {
+		err.Kind = kind.Value
+	}
+
+	if stack, ok := hashField(hash, "stack").(*object.Array); ok {
+		for _, frame := range stack.Elements {
+			err.Stack = append(err.Stack, frame.Inspect())
 		}
 	}
 
-	return &object.Hash{
-		Pairs: pairs,
+	if v := hashField(hash, "value"); v != nil && v != NULL {
+		err.Value = v
+	}
+
+	return err
+}
+
+// errorToHash convert caught Error to {message, kind, stack, value}
+func errorToHash(err *object.Error) *object.Hash {
+	stack := &object.Array{
+		Elements: []object.Object{},
+	}
+	for _, frame := range err.Stack {
+		stack.Elements = append(stack.Elements, &object.String{Value: frame})
+	}
+
+	var value object.Object = NULL
+	if err.Value != nil {
+		value = err.Value
+	}
+
+	fields := map[string]object.Object{
+		"message": &object.String{Value: err.Message},
+		"kind":    &object.String{Value: err.Kind},
+		"stack":   stack,
+		"value":   value,
 	}
+
+	hash := object.NewHash()
+	for k, v := range fields {
+		hash.Set(&object.String{Value: k}, v)
+	}
+
+	return hash
+}
+
+func hashField(hash *object.Hash, name string) object.Object {
+	value, ok := hash.Get(&object.String{Value: name})
+	if !ok {
+		return nil
+	}
+
+	return value
 }
@@ -1,7 +1,16 @@
 package evaluator
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/naoto0822/monkey-interpreter/pkg/lexer"
 	"github.com/naoto0822/monkey-interpreter/pkg/object"
@@ -27,6 +36,9 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"2 * (5 + 10)", 30},
 		{"3 * 3 * 3 + 10", 37},
 		{"3 * (3 * 3) + 10", 37},
+		{"10 % 3", 1},
+		{"-7 % 3", -1},
+		{"1 + 10 % 4 * 2", 5},
 	}
 
 	for _, tt := range tests {
@@ -66,6 +78,41 @@ func TestEvalBooleanExpression(t *testing.T) {
 	}
 }
 
+func TestStructuralEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"a" == "a"`, true},
+		{`"a" != "a"`, false},
+		{`"a" == "b"`, false},
+		{`"a" < "b"`, true},
+		{`"b" < "a"`, false},
+		{`"b" > "a"`, true},
+		{`"abc" > "ab"`, true},
+		{"[1, 2] == [1, 2]", true},
+		{"[1, 2] != [1, 2]", false},
+		{"[1, 2] == [2, 1]", false},
+		{"[1, 2] == [1, 2, 3]", false},
+		{`[1, [2, "x"]] == [1, [2, "x"]]`, true},
+		{"[] == []", true},
+		{`{"a": 1} == {"a": 1}`, true},
+		{`{"a": 1, "b": [2]} == {"b": [2], "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{`{"a": 1} == {"b": 1}`, false},
+		{`{"a": 1} == {"a": 1, "b": 2}`, false},
+		{`[1] == {"a": 1}`, false},
+		{`1 == "1"`, false},
+		{`let f = fn() { 1 }; f == f`, true},
+		{`fn() { 1 } == fn() { 1 }`, false},
+	}
+
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+		testBooleanObject(t, obj, tt.expected)
+	}
+}
+
 func TestBangOperator(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -167,6 +214,14 @@ func TestErrorHandling(t *testing.T) {
 			`"Hello" - "World"`,
 			"unknown operator: STRING - STRING",
 		},
+		{
+			`{[fn(x) { x }]: 1}`,
+			"unusable as hash key: ARRAY",
+		},
+		{
+			`{"a": 1}[[1, fn(x) { x }]]`,
+			"unusable as hash key: ARRAY",
+		},
 	}
 
 	for _, tt := range tests {
@@ -343,221 +398,1625 @@ func TestBuiltinFunctions(t *testing.T) {
 	}
 }
 
-func TestArrayLiteral(t *testing.T) {
-	input := `[1, 2 * 2, 3 + 3]`
-	obj := testEval(input)
+func TestArrayBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`rest([1, 2, 3])`, []interface{}{2, 3}},
+		{`rest([1])`, []interface{}{}},
+		{`rest([])`, nil},
+		{`rest(1)`, errorMessage("argument to `rest` must be ARRAY, got INTEGER")},
+		{`map([1, 2, 3], fn(x) { x * 2 })`, []interface{}{2, 4, 6}},
+		{`map([], fn(x) { x * 2 })`, []interface{}{}},
+		{`map([1, "a"], len)`, errorMessage("argument to `len` not supported, got INTEGER")},
+		{`map([1], 1)`, errorMessage("argument to `map` must be FUNCTION, got INTEGER")},
+		{`map([1], fn(x, y) { x })`, errorMessage("wrong number of arguments. got=1, want=2")},
+		{`map([1], fn(x) { })`, []interface{}{nil}},
+		{`filter([1, 2, 3, 4], fn(x) { x > 2 })`, []interface{}{3, 4}},
+		{`reduce([1, 2, 3], fn(acc, x) { acc + x }, 10)`, 16},
+		{`reduce([1, 2, 3], fn(acc, x) { acc + x })`, 6},
+		{`reduce([], fn(acc, x) { acc + x })`, nil},
+		{`reduce([], fn(acc, x) { acc + x }, 0)`, 0},
+		{`sort([3, 1, 2])`, []interface{}{1, 2, 3}},
+		{`sort(["b", "c", "a"])`, []interface{}{"a", "b", "c"}},
+		{`sort([[2, 1], [1, 2]])`, []interface{}{[]interface{}{1, 2}, []interface{}{2, 1}}},
+		{`sort([3, 1, 2], fn(a, b) { b - a })`, []interface{}{3, 2, 1}},
+		{`sort([3, 1, 2], fn(a, b) { a > b })`, []interface{}{3, 2, 1}},
+		{`sort([1, "a"])`, errorMessage("unable to compare STRING and INTEGER")},
+		{`sort([1, 2], fn(a, b) { "x" })`, errorMessage("comparator of `sort` must return INTEGER or BOOLEAN, got STRING")},
+		{`let a = [3, 1, 2]; sort(a); a`, []interface{}{3, 1, 2}},
+		{`reverse([1, 2, 3])`, []interface{}{3, 2, 1}},
+		{`contains([1, [2], "a"], [2])`, true},
+		{`contains([1, 2], 3)`, false},
+		{`contains(1, 3)`, errorMessage("argument to `contains` not supported, got INTEGER")},
+		{`index_of([1, 2, 3], 3)`, 2},
+		{`index_of([1, 2, 3], 4)`, -1},
+		{`concat([1], [2, 3], [])`, []interface{}{1, 2, 3}},
+		{`concat()`, []interface{}{}},
+		{`concat([1], 2)`, errorMessage("argument to `concat` must be ARRAY, got INTEGER")},
+		{`flatten([1, [2, [3, [4]]]])`, []interface{}{1, 2, 3, 4}},
+		{`flatten([1, [2, [3, [4]]]], 1)`, []interface{}{1, 2, []interface{}{3, []interface{}{4}}}},
+		{`zip([1, 2, 3], ["a", "b"])`, []interface{}{[]interface{}{1, "a"}, []interface{}{2, "b"}}},
+		{`zip()`, errorMessage("wrong number of arguments. got=0, want>=1")},
+		{`let sum = fn(arr) { if (len(arr) == 0) { 0 } else { first(arr) + sum(rest(arr)) } }; sum([1, 2, 3])`, 6},
+	}
 
-	array, ok := obj.(*object.Array)
-	if !ok {
-		t.Errorf("obj is not object.Array. got=%T", obj)
-		return
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+		testExpectedObject(t, obj, tt.expected)
 	}
+}
 
-	if len(array.Elements) != 3 {
-		t.Errorf("array.Elements does not contain 3. got=%d", len(array.Elements))
-		return
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`split("a,b,c", ",")`, []interface{}{"a", "b", "c"}},
+		{`split(" a  b ")`, []interface{}{"a", "b"}},
+		{`split(1, ",")`, errorMessage("argument to `split` must be STRING, got INTEGER")},
+		{`join(["a", "b", "c"], "-")`, "a-b-c"},
+		{`join([1, "b"])`, "1b"},
+		{`join("a", "-")`, errorMessage("argument to `join` must be ARRAY, got STRING")},
+		{`trim("  a b  ")`, "a b"},
+		{`trim("xxaxx", "x")`, "a"},
+		{`upper("abc")`, "ABC"},
+		{`lower("ABC")`, "abc"},
+		{`upper(1)`, errorMessage("argument to `upper` must be STRING, got INTEGER")},
+		{`replace("aaa", "a", "b")`, "bbb"},
+		{`replace("aaa", "a", "b", 2)`, "bba"},
+		{`starts_with("monkey", "mon")`, true},
+		{`starts_with("monkey", "key")`, false},
+		{`ends_with("monkey", "key")`, true},
+		{`contains("monkey", "nk")`, true},
+		{`contains("monkey", "x")`, false},
+		{`contains("monkey", 1)`, errorMessage("argument to `contains` must be STRING, got INTEGER")},
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", -1)`, errorMessage("argument to `repeat` must not be negative, got -1")},
+		{`repeat("ab", 9223372036854775807)`, errorMessage("result of `repeat` must not exceed 16777216 bytes, got 2 * 9223372036854775807")},
+		{`repeat("", 9223372036854775807)`, ""},
+		{`chars("héllo")`, []interface{}{"h", "é", "l", "l", "o"}},
+		{`pad_left("7", 3, "0")`, "007"},
+		{`pad_left("7", 4, "ab")`, "aba7"},
+		{`pad_right("ab", 4)`, "ab  "},
+		{`pad_right("abcde", 4)`, "abcde"},
+		{`pad_left("a", 3, "")`, errorMessage("argument to `pad_left` must not be empty")},
+		{`pad_left("a", 9223372036854775807)`, errorMessage("argument to `pad_left` must not exceed 16777216, got 9223372036854775807")},
+		{`pad_right("a", 16777217, "x")`, errorMessage("argument to `pad_right` must not exceed 16777216, got 16777217")},
+		{`pad_right("a", 3, "xyzxyz")`, "axy"},
+		{`format("%s is %d years", "monkey", 5)`, "monkey is 5 years"},
+		{`format("%v and %v", [1, 2], true)`, "[1, 2] and true"},
+		{`format("100%%")`, "100%"},
+		{`format("%d", "a")`, errorMessage("format: %d must be INTEGER, got STRING")},
+		{`format("%s %s", "a")`, errorMessage("format: missing argument for %s")},
+		{`format("%s", "a", "b")`, errorMessage("format: too many arguments. got=2, want=1")},
+		{`format("%x", 1)`, errorMessage("format: unknown verb %x")},
 	}
 
-	if !testIntegerObject(t, array.Elements[0], 1) {
-		return
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+		testExpectedObject(t, obj, tt.expected)
 	}
+}
 
-	if !testIntegerObject(t, array.Elements[1], 4) {
-		return
+func TestHashBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`keys({"b": 1, "a": 2, "c": 3})`, []interface{}{"a", "b", "c"}},
+		{`keys({2: 1, 10: 2, 1: 3})`, []interface{}{1, 2, 10}},
+		{`keys({"a": 1, 1: 2, true: 3})`, []interface{}{true, 1, "a"}},
+		{`keys({})`, []interface{}{}},
+		{`keys([])`, errorMessage("argument to `keys` must be HASH, got ARRAY")},
+		{`values({"b": 1, "a": 2})`, []interface{}{2, 1}},
+		{`items({"b": 1, "a": 2})`, []interface{}{[]interface{}{"a", 2}, []interface{}{"b", 1}}},
+		{`has({"a": 1}, "a")`, true},
+		{`has({"a": 1}, "b")`, false},
+		{`has({"a": 1}, fn(x) { x })`, errorMessage("unusable as hash key: FUNCTION")},
+		{`get({"a": 1}, "a")`, 1},
+		{`get({"a": 1}, "b")`, nil},
+		{`get({"a": 1}, "b", 2)`, 2},
+		{`get({"a": 1}, fn(x) { x }, 2)`, errorMessage("unusable as hash key: FUNCTION")},
+		{`set({"a": 1}, "b", 2)["b"]`, 2},
+		{`let h = {"a": 1}; set(h, "a", 2); h["a"]`, 1},
+		{`set({"a": 1}, "a", 2) == {"a": 2}`, true},
+		{`delete({"a": 1, "b": 2}, "a") == {"b": 2}`, true},
+		{`let h = {"a": 1}; delete(h, "a"); h["a"]`, 1},
+		{`merge({"a": 1, "b": 2}, {"b": 3}, {"c": 4}) == {"a": 1, "b": 3, "c": 4}`, true},
+		{`merge({"a": 1}, 1)`, errorMessage("argument to `merge` must be HASH, got INTEGER")},
 	}
 
-	if !testIntegerObject(t, array.Elements[2], 6) {
-		return
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+		testExpectedObject(t, obj, tt.expected)
 	}
 }
 
-func TestArrayIndexExpression(t *testing.T) {
+func TestTypeBuiltins(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{
-			"[1, 2, 3][0]",
-			1,
-		},
-		{
-			"[1, 2, 3][1]",
-			2,
-		},
-		{
-			"[1, 2, 3][2]",
-			3,
-		},
-		{
-			"let i = 0; [1][i];",
-			1,
-		},
-		{
-			"[1, 2, 3][1 + 1]",
-			3,
-		},
-		{
-			"let myArray = [1, 2, 3]; myArray[2];",
-			3,
-		},
-		{
-			"let myArray = [1, 2, 3]; myArray[0] + myArray[1] + myArray[2];",
-			6,
-		},
-		{
-			"let myArray = [1, 2, 3]; let i = myArray[0]; myArray[i];",
-			2,
-		},
-		{
-			"[1, 2, 3][3]",
-			nil,
-		},
-		{
-			"[1, 2, 3][-1]",
-			nil,
-		},
+		{`type(1)`, "INTEGER"},
+		{`type("a")`, "STRING"},
+		{`type(true)`, "BOOLEAN"},
+		{`type([1])`, "ARRAY"},
+		{`type({})`, "HASH"},
+		{`type(fn(x) { x })`, "FUNCTION"},
+		{`type(len)`, "BUILTIN"},
+		{`type(if (false) { 1 })`, "NULL"},
+		{`str(10)`, "10"},
+		{`str("a")`, "a"},
+		{`str([1, "a"])`, "[1, a]"},
+		{`str(true) + "!"`, "true!"},
+		{`int("42")`, 42},
+		{`int("-7")`, -7},
+		{`int(3)`, 3},
+		{`int(true)`, 1},
+		{`int(false)`, 0},
+		{`int("4.2")`, errorMessage(`could not parse "4.2" as integer`)},
+		{`int("")`, errorMessage(`could not parse "" as integer`)},
+		{`int([1])`, errorMessage("argument to `int` not supported, got ARRAY")},
+		{`bool(0)`, true},
+		{`bool("")`, true},
+		{`bool(false)`, false},
+		{`bool(if (false) { 1 })`, false},
+		{`is_int(1)`, true},
+		{`is_int("1")`, false},
+		{`is_string("1")`, true},
+		{`is_bool(false)`, true},
+		{`is_array([])`, true},
+		{`is_hash({})`, true},
+		{`is_hash([])`, false},
+		{`is_null(if (false) { 1 })`, true},
+		{`is_function(fn() { 1 })`, true},
+		{`is_function(len)`, true},
+		{`is_function(1)`, false},
+		{`is_int()`, errorMessage("wrong number of arguments. got=0, want=1")},
 	}
 
 	for _, tt := range tests {
 		obj := testEval(tt.input)
+		testExpectedObject(t, obj, tt.expected)
+	}
+}
 
-		integer, ok := tt.expected.(int)
-		if ok {
-			testIntegerObject(t, obj, int64(integer))
-		} else {
-			testNullObject(t, obj)
-		}
+func TestValueErrorIsCatchable(t *testing.T) {
+	input := `try { int("x") } catch (e) { e["kind"] }`
+
+	testStringObject(t, testEval(input), "ValueError")
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`10 / 0`, errorMessage("division by zero")},
+		{`10 % 0`, errorMessage("division by zero")},
+		{`abs(-3)`, 3},
+		{`abs(3)`, 3},
+		{`abs(-9223372036854775807 - 1)`, errorMessage("abs(-9223372036854775808) overflows INTEGER")},
+		{`abs("a")`, errorMessage("argument to `abs` must be INTEGER, got STRING")},
+		{`min(3, 1, 2)`, 1},
+		{`min([3, 1, 2])`, 1},
+		{`max(3, 1, 2)`, 3},
+		{`max([-3, -1])`, -1},
+		{`max([])`, errorMessage("argument to `max` must not be empty")},
+		{`min(1, "a")`, errorMessage("argument to `min` must be INTEGER, got STRING")},
+		{`sum([1, 2, 3])`, 6},
+		{`sum([])`, 0},
+		{`sum([9223372036854775807, -1, 1])`, 9223372036854775807},
+		{`sum([9223372036854775807, 1])`, errorMessage("sum overflows INTEGER at [1]")},
+		{`sum([-9223372036854775807, -1, -1])`, errorMessage("sum overflows INTEGER at [2]")},
+		{`sum([1, true])`, errorMessage("argument to `sum` must be INTEGER, got BOOLEAN")},
+		{`pow(2, 10)`, 1024},
+		{`pow(-3, 3)`, -27},
+		{`pow(5, 0)`, 1},
+		{`pow(2, 62)`, 4611686018427387904},
+		{`pow(-2, 63)`, -9223372036854775807 - 1},
+		{`pow(2, 63)`, errorMessage("pow(2, 63) overflows INTEGER")},
+		{`pow(3, 1000)`, errorMessage("pow(3, 1000) overflows INTEGER")},
+		{`pow(-1, 9223372036854775807)`, -1},
+		{`pow(2, -1)`, errorMessage("argument to `pow` must not be negative, got -1")},
+		{`sqrt(16)`, 4},
+		{`sqrt(17)`, 4},
+		{`sqrt(0)`, 0},
+		{`sqrt(9223372036854775807)`, 3037000499},
+		{`sqrt(-1)`, errorMessage("square root of negative number -1")},
+		{`floor(5)`, 5},
+		{`floor(7, 2)`, 3},
+		{`floor(-7, 2)`, -4},
+		{`floor(7, -2)`, -4},
+		{`floor(-8, 2)`, -4},
+		{`ceil(7, 2)`, 4},
+		{`ceil(-7, 2)`, -3},
+		{`ceil(8, 2)`, 4},
+		{`round(7, 2)`, 4},
+		{`round(-7, 2)`, -4},
+		{`round(10, 4)`, 3},
+		{`round(9, 4)`, 2},
+		{`round(-9, 4)`, -2},
+		{`round(1, 0)`, errorMessage("division by zero")},
+		{`gcd(12, 18)`, 6},
+		{`gcd(-12, 18)`, 6},
+		{`gcd(0, 5)`, 5},
+		{`gcd(-9223372036854775807 - 1, 0)`, errorMessage("gcd(-9223372036854775808, 0) overflows INTEGER")},
+		{`gcd(-9223372036854775807 - 1, 6)`, 2},
+		{`clamp(5, 0, 3)`, 3},
+		{`clamp(-1, 0, 3)`, 0},
+		{`clamp(2, 0, 3)`, 2},
+		{`clamp(2, 3, 0)`, errorMessage("argument to `clamp` has lo > hi, got 3 > 0")},
+		{`range(3)`, []interface{}{0, 1, 2}},
+		{`range(1, 4)`, []interface{}{1, 2, 3}},
+		{`range(0, 10, 3)`, []interface{}{0, 3, 6, 9}},
+		{`range(3, 0, -1)`, []interface{}{3, 2, 1}},
+		{`range(3, 0)`, []interface{}{}},
+		{`range(0, 3, 0)`, errorMessage("argument to `range` must not be zero step")},
+		{`range(9223372036854775806, 9223372036854775807, 5)`, []interface{}{9223372036854775806}},
+		{`range(0, 9223372036854775807, 4611686018427387904)`, []interface{}{0, 4611686018427387904}},
+		{`range(-9223372036854775807 - 1, 9223372036854775807, 9223372036854775807)`, []interface{}{-9223372036854775807 - 1, -1, 9223372036854775806}},
+		{`range(9223372036854775807, -9223372036854775807 - 1, -9223372036854775807 - 1)`, []interface{}{9223372036854775807, -1}},
+		{`range(9223372036854775807)`, errorMessage("range has 9223372036854775807 elements, must not exceed 16777216")},
+		{`range()`, errorMessage("wrong number of arguments. got=0, want=1 to 3")},
+	}
+
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+		testExpectedObject(t, obj, tt.expected)
 	}
 }
 
-func TestParseHashLiteral(t *testing.T) {
-	input := `let two = "two";
+func TestJSONBuiltins(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Set("src", &object.String{Value: `{"a": [1, true, null], "b": "x"}`})
+	env.Set("nothing", NULL)
 
-{
- "one": 10 - 9,
- two: 1 + 1,
- "thr" + "ee": 6 / 2,
- 4: 4,
- true: 5,
- false: 6
-}`
-	obj := testEval(input)
-	hash, ok := obj.(*object.Hash)
-	if !ok {
-		t.Errorf("obj is not object.Hash. got=%T", obj)
-		return
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`json_parse(src)["a"]`, []interface{}{1, true, nil}},
+		{`json_parse(src)["b"]`, "x"},
+		{`json_parse(src) == {"a": [1, true, nothing], "b": "x"}`, true},
+		{`json_parse("null")`, nil},
+		{`json_parse("1.5")`, errorMessage("json: number 1.5 is not integer")},
+		{`json_parse("[1,")`, errorMessage("json: unexpected EOF")},
+		{`json_parse(1)`, errorMessage("argument to `json_parse` must be STRING, got INTEGER")},
+		{`json_stringify({"b": [1, "x"], "a": nothing})`, `{"a":null,"b":[1,"x"]}`},
+		{`json_stringify([1], 2)`, "[\n  1\n]"},
+		{`json_stringify([1], "--")`, "[\n--1\n]"},
+		{`json_stringify([1], 9223372036854775807)`, errorMessage("argument to `json_stringify` must not exceed 10, got 9223372036854775807")},
+		{`json_stringify([1], repeat(" ", 11))`, errorMessage("argument to `json_stringify` must not be longer than 10, got 11")},
+		{`json_stringify({1: 2})`, errorMessage("json: unsupported key type INTEGER, keys must be STRING")},
+		{`json_stringify([fn(x) { x }])`, errorMessage("json: unsupported type FUNCTION")},
+		{`json_stringify(1, true)`, errorMessage("argument to `json_stringify` must be INTEGER or STRING, got BOOLEAN")},
+		{`json_parse(json_stringify(json_parse(src))) == json_parse(src)`, true},
 	}
 
-	expected := map[object.HashKey]int64{
-		(&object.String{Value: "one"}).HashKey():   1,
-		(&object.String{Value: "two"}).HashKey():   2,
-		(&object.String{Value: "three"}).HashKey(): 3,
-		(&object.Integer{Value: 4}).HashKey():      4,
-		TRUE.HashKey():                             5,
-		FALSE.HashKey():                            6,
+	for _, tt := range tests {
+		obj := testEvalWithEnv(tt.input, env)
+		testExpectedObject(t, obj, tt.expected)
 	}
+}
 
-	if len(hash.Pairs) != 6 {
-		t.Errorf("hash.Pairs does not contain 6. got=%d", len(hash.Pairs))
-		return
+func TestRegexBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`match("(\w+)=(\d+)", "a=1 b=2")`, []interface{}{"a=1", "a", "1"}},
+		{`match(regex("(\w+)=(\d+)"), "a=1")[2]`, "1"},
+		{`match("x", "abc")`, nil},
+		{`if (match("^ERROR", "ERROR: disk")) { 1 } else { 2 }`, 1},
+		{`find_all("\d+", "a1 b22 c333")`, []interface{}{"1", "22", "333"}},
+		{`find_all("\d+", "a1 b22 c333", 2)`, []interface{}{"1", "22"}},
+		{`find_all("\d+", "abc")`, []interface{}{}},
+		{`replace_re("(\w+)@(\w+)", "me@host", "$2:$1")`, "host:me"},
+		{`replace_re("\s+", "a  b   c", " ")`, "a b c"},
+		{`split_re(",\s*", "a, b,c")`, []interface{}{"a", "b", "c"}},
+		{`split_re(",", "a,b,c", 2)`, []interface{}{"a", "b,c"}},
+		{`type(regex("a"))`, "REGEX"},
+		{`regex("a+")`, "/a+/"},
+		{`regex("a") == regex("a")`, true},
+		{`regex("a") == regex("b")`, false},
+		{`match("(a", "a")`, errorMessage("invalid pattern to `match`: error parsing regexp: missing closing ): `(a`")},
+		{`match(1, "a")`, errorMessage("argument to `match` must be REGEX or STRING, got INTEGER")},
+		{`match("a", 1)`, errorMessage("argument to `match` must be STRING, got INTEGER")},
 	}
 
-	for k, v := range expected {
-		pair, ok := hash.Pairs[k]
-		if !ok {
-			t.Errorf("no pair for given key in Pairs")
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+		if re, ok := obj.(*object.Regex); ok {
+			testExpectedObject(t, &object.String{Value: re.Inspect()}, tt.expected)
 			continue
 		}
 
-		testIntegerObject(t, pair.Value, v)
+		testExpectedObject(t, obj, tt.expected)
 	}
 }
 
-func TestHashIndexExpression(t *testing.T) {
+func TestRegexCache(t *testing.T) {
+	first, err := compileRegex("a+b")
+	if err != nil {
+		t.Fatalf("compileRegex returned error: %s", err)
+	}
+
+	second, _ := compileRegex("a+b")
+	if first != second {
+		t.Errorf("compileRegex does not reuse compiled pattern")
+	}
+
+	for i := 0; i < regexCacheSize*2; i++ {
+		compileRegex(strings.Repeat("a", i+1))
+	}
+
+	if len(regexCache.patterns) > regexCacheSize {
+		t.Errorf("regexCache exceeds %d. got=%d", regexCacheSize, len(regexCache.patterns))
+	}
+}
+
+func TestTimeBuiltins(t *testing.T) {
+	env := object.NewEnvironment()
+	SetClock(env, func() time.Time {
+		return time.Date(2020, 4, 1, 12, 30, 0, 0, time.UTC)
+	})
+
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{
-			`{"foo": 5}["foo"]`, 5,
-		},
-		{
-			`{"foo": 5}["bar"]`, nil,
-		},
-		{
-			`let key = "foo"; {"foo": 5}[key]`,
-			5,
-		},
-		{
-			`{}["foo"]`,
-			nil,
-		},
-		{
-			`{5: 5}[5]`,
-			5,
-		},
-		{
-			`{true: 5}[true]`,
-			5,
-		},
+		{`time_format(now())`, "2020-04-01T12:30:00Z"},
+		{`time_format(now(), "2006/01/02 15:04")`, "2020/04/01 12:30"},
+		{`time_format(now() + 90)`, "2020-04-01T12:31:30Z"},
+		{`time_format(60 + now())`, "2020-04-01T12:31:00Z"},
+		{`time_format(now() - 3600)`, "2020-04-01T11:30:00Z"},
+		{`time_parse("2020-04-02T12:30:00Z") - now()`, 86400},
+		{`time_parse("2020-04-01T21:30:00+09:00") == now()`, true},
+		{`time_parse("01/04/2020", "02/01/2006") < now()`, true},
+		{`now() > now() + 1`, false},
+		{`now() != now()`, false},
+		{`sort([now(), now() - 1])[0] == now() - 1`, true},
+		{`time_unix(now())`, 1585744200},
+		{`time_from_unix(1585744200) == now()`, true},
+		{`type(now())`, "TIME"},
+		{`now() * now()`, errorMessage("unknown operator: TIME * TIME")},
+		{`now() + "a"`, errorMessage("type mismatch: TIME + STRING")},
+		{`1 - now()`, errorMessage("type mismatch: INTEGER - TIME")},
+		{`time_parse("yesterday")`, errorMessage(`parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`)},
+		{`time_format(1)`, errorMessage("argument to `time_format` must be TIME, got INTEGER")},
 	}
 
 	for _, tt := range tests {
-		obj := testEval(tt.input)
-		expectedInt, ok := tt.expected.(int)
-		if ok {
-			testIntegerObject(t, obj, int64(expectedInt))
-		} else {
-			testNullObject(t, obj)
-		}
+		obj := testEvalWithEnv(tt.input, env)
+		testExpectedObject(t, obj, tt.expected)
 	}
 }
 
-func testEval(input string) object.Object {
-	l := lexer.New(input)
-	p := parser.New(l)
-	program := p.ParseProgram()
+func TestFileBuiltins(t *testing.T) {
+	root, err := ioutil.TempDir("", "monkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	outside, err := ioutil.TempDir("", "monkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+
+	if err := os.Mkdir(filepath.Join(root, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "pwned.txt"), filepath.Join(root, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "none"), filepath.Join(root, "dangling_dir")); err != nil {
+		t.Fatal(err)
+	}
+
 	env := object.NewEnvironment()
+	SetFileSystem(env, &FileSystem{Root: root, Read: true, Write: true})
 
-	return Eval(program, env)
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`exists("a.txt")`, false},
+		{`write_file("a.txt", "hello")`, nil},
+		{`exists("a.txt")`, true},
+		{`read_file("a.txt")`, "hello"},
+		{`append_file("a.txt", ", world")`, nil},
+		{`read_file("/a.txt")`, "hello, world"},
+		{`write_file("dir/b.txt", "b"); list_dir("dir")`, []interface{}{"b.txt"}},
+		{`list_dir()`, []interface{}{"a.txt", "dangling", "dangling_dir", "dir", "link"}},
+		{`read_file("dir/../a.txt")`, "hello, world"},
+		{`read_file("none.txt")`, errorMessage("open none.txt: no such file or directory")},
+		{`read_file("../a.txt")`, errorMessage("read_file: ../a.txt: path escapes root")},
+		{`write_file("link/x.txt", "x")`, errorMessage("write_file: link/x.txt: path escapes root")},
+		{`list_dir("link")`, errorMessage("list_dir: link: path escapes root")},
+		{`write_file("dangling", "x")`, errorMessage("write_file: dangling: path escapes root")},
+		{`append_file("dangling_dir/x.txt", "x")`, errorMessage("append_file: dangling_dir/x.txt: path escapes root")},
+		{`read_file(1)`, errorMessage("argument to `read_file` must be STRING, got INTEGER")},
+	}
+
+	for _, tt := range tests {
+		obj := testEvalWithEnv(tt.input, env)
+		testExpectedObject(t, obj, tt.expected)
+	}
+
+	for _, name := range []string{"x.txt", "pwned.txt", "none"} {
+		if _, err := os.Stat(filepath.Join(outside, name)); !os.IsNotExist(err) {
+			t.Errorf("file builtins wrote %s outside of root", name)
+		}
+	}
 }
 
-func testNullObject(t *testing.T, obj object.Object) bool {
-	if obj != NULL {
-		t.Errorf("obj is not NULL. got=%T", obj)
-		return false
+func TestFileBuiltinsPermission(t *testing.T) {
+	tests := []struct {
+		fs       *FileSystem
+		input    string
+		expected string
+	}{
+		{nil, `read_file("a.txt")`, "read_file: file system access is disabled"},
+		{nil, `exists("a.txt")`, "exists: file system access is disabled"},
+		{&FileSystem{Root: ".", Read: true}, `write_file("a.txt", "")`, "write_file: file system is not writable"},
+		{&FileSystem{Root: ".", Write: true}, `list_dir()`, "list_dir: file system is not readable"},
 	}
 
-	return true
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		if tt.fs != nil {
+			SetFileSystem(env, tt.fs)
+		}
+
+		obj := testEvalWithEnv(tt.input, env)
+		testExpectedObject(t, obj, errorMessage(tt.expected))
+	}
 }
 
-func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
-	intObj, ok := obj.(*object.Integer)
-	if !ok {
-		t.Errorf("obj is not object.Integer. got=%T", obj)
-		return false
+func TestImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"lib/math.mk": `
+			let helper = fn(x) { x * 2 };
+			export let double = fn(x) { helper(x) };
+			export let pi = 3;
+			puts("loaded");
+		`,
+		"a.mk":        `import "b"; export let x = 1;`,
+		"b.mk":        `import "a"; export let y = 2;`,
+		"broken.mk":   `export let x = ;`,
+		"failing.mk":  `throw "boom";`,
+		"bad-name.mk": `export let x = 1;`,
+		"p.mk":        `let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } }; fib(18); import "q"; export let x = 1;`,
+		"q.mk":        `let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } }; fib(18); import "p"; export let y = 2;`,
 	}
 
-	if intObj.Value != expected {
-		t.Errorf("intObj.Value is not %d. got=%d", expected, intObj.Value)
-		return false
+	if err := os.Mkdir(filepath.Join(dir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
 	}
 
-	return true
+	var out bytes.Buffer
+	env := object.NewEnvironment()
+	SetOutput(env, &out)
+	SetImportPath(env, filepath.Join(dir, "none"), dir)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`import "lib/math"; math["double"](4)`, 8},
+		{`import "lib/math.mk" as m; m["pi"]`, 3},
+		{`import "lib/math"; type(math)`, "MODULE"},
+		{`import "lib/math"; math["helper"]`, errorMessage("module math has no export helper")},
+		{`import "lib/math"; math[1]`, errorMessage("module index must be STRING, got INTEGER")},
+		{`import "lib/math"; math.double(math.pi)`, 6},
+		{`import "lib/math"; math.helper`, errorMessage("module math has no export helper")},
+		{`import "bad-name" as bad; bad["x"]`, 1},
+		{`import "bad-name"`, errorMessage(`import "bad-name": module name is not identifier, use import "bad-name" as name`)},
+		{`import "none"`, errorMessage(`import "none": module not found`)},
+		{`import "a"`, errorMessage("import cycle: a -> b -> a")},
+		{`import "broken"`, errorMessage(`import "broken": no prefix parse function for ; found.`)},
+		{`import "failing"`, errorMessage("boom")},
+		{`let f = fn() { import "lib/math"; math["pi"] }; f()`, 3},
+	}
+
+	for _, tt := range tests {
+		obj := testEvalWithEnv(tt.input, env)
+		testExpectedObject(t, obj, tt.expected)
+	}
+
+	if out.String() != "loaded\n" {
+		t.Errorf("module is not evaluated once. got=%q", out.String())
+	}
+
+	obj := testEval(`import "lib/math"`)
+	testExpectedObject(t, obj, errorMessage(`import "lib/math": import path is not set`))
+
+	// module can not be imported out of search dir
+	libEnv := object.NewEnvironment()
+	SetImportPath(libEnv, filepath.Join(dir, "lib"))
+	obj = testEvalWithEnv(`import "../bad-name" as bad`, libEnv)
+	testExpectedObject(t, obj, errorMessage(`import "../bad-name": module not found`))
+
+	// tasks importing p -> q and q -> p must not wait for each other forever
+	done := make(chan object.Object, 1)
+	go func() {
+		done <- testEvalWithEnv(`
+let cycle = fn(f) { try { await(spawn(f)); false } catch (e) { contains(e["message"], "import cycle") } };
+let p = spawn(cycle, fn() { import "p" });
+let q = spawn(cycle, fn() { import "q" });
+[await(p), await(q)]
+`, env)
+	}()
+
+	select {
+	case obj := <-done:
+		testExpectedObject(t, obj, []interface{}{true, true})
+	case <-time.After(10 * time.Second):
+		t.Fatalf("import cycle across tasks is not detected")
+	}
 }
 
-func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
-	boolObj, ok := obj.(*object.Boolean)
-	if !ok {
-		t.Errorf("obj is not object.Boolean. got=%T", obj)
-		return false
+func TestImportLimitError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monkey")
+	if err != nil {
+		t.Fatal(err)
 	}
+	defer os.RemoveAll(dir)
 
-	if boolObj.Value != expected {
-		t.Errorf("boolObj.Value is not %t. got=%t", expected, boolObj.Value)
-		return false
+	src := `let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } }; f(20); export let x = 1;`
+	if err := ioutil.WriteFile(filepath.Join(dir, "deep.mk"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := object.NewEnvironment()
+	SetImportPath(env, dir)
+
+	// limit of one evaluation must not break import of others
+	limited := object.NewEnclosedEnvironment(env)
+	SetMaxCallDepth(limited, 10)
+	obj := testEvalWithEnv(`import "deep"`, limited)
+	testExpectedObject(t, obj, errorMessage("maximum call depth 10 exceeded"))
+
+	obj = testEvalWithEnv(`import "deep"; deep.x`, env)
+	testExpectedObject(t, obj, 1)
+}
+
+func TestDotExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let p = {"x": 1, "y": 2}; p.x + p.y`, 3},
+		{`let p = {"user": {"name": "monkey"}}; p.user.name`, "monkey"},
+		{`{"x": 1}.z`, nil},
+		{`{"keys": 1}.keys`, 1},
+		{`{"a": 1, "b": 2}.keys()`, []interface{}{"a", "b"}},
+		{`let h = {"f": fn(x) { x * 2 }}; h.f(3)`, 6},
+		{`"abc".upper()`, "ABC"},
+		{`" a b ".trim().split(" ")`, []interface{}{"a", "b"}},
+		{`[1, 2, 3].map(fn(x) { x * 2 }).filter(fn(x) { x > 2 })`, []interface{}{4, 6}},
+		{`[1, 2, 3].reduce(fn(acc, x) { acc + x }, 0)`, 6},
+		{`["a", "b"].join(",").len()`, 3},
+		{`let upper = "abc".upper; upper()`, "ABC"},
+		{`-5.abs()`, -5},
+		{`(-5).abs()`, 5},
+		{`regex("a+").replace("caat", "o")`, "cot"},
+		{`5.type()`, "INTEGER"},
+		{`[1].str()`, "[1]"},
+		{`true.upper()`, errorMessage("BOOLEAN has no method upper")},
+		{`"abc".map(fn(x) { x })`, errorMessage("STRING has no method map")},
+		{`"abc".repeat("x")`, errorMessage("argument to `repeat` must be INTEGER, got STRING")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if tt.expected == nil {
+			testNullObject(t, evaluated)
+			continue
+		}
+		testExpectedObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`struct Point { x, y }; let p = Point(1, 2); p.x + p["y"]`, 3},
+		{`struct Point { x, y }; type(Point(1, 2))`, "Point"},
+		{`struct Point { x, y }; Point(1, 2).type()`, "Point"},
+		{`struct Point { x, y }; str(Point(1, "a"))`, `Point{x: 1, y: a}`},
+		{`struct Point { x, y }; Point(1, 2) == Point(1, 2)`, true},
+		{`struct Point { x, y }; Point(1, 2) == Point(2, 1)`, false},
+		{`struct A { x }; struct B { x }; A(1) == B(1)`, false},
+		{`struct Line { from, to }; struct Point { x, y }; Line(Point(0, 0), Point(1, 2)).to.y`, 2},
+		{`struct Point { x, y }; [Point(3, 4)].map(fn(p) { p.x * p.y })`, []interface{}{12}},
+		{`struct Point { x, y }; Point(1, 2).z`, errorMessage("Point has no field z")},
+		{`struct Point { x, y }; Point(1, 2)["z"]`, errorMessage("Point has no field z")},
+		{`struct Point { x, y }; Point(1, 2)[0]`, errorMessage("struct index must be STRING, got INTEGER")},
+		{`struct Point { x, y }; Point(1)`, errorMessage("wrong number of arguments. got=1, want=2")},
+		{`struct Point { x, y }; type(Point)`, "STRUCT_TYPE"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testExpectedObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestQuoteUnquote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(5)`, `5`},
+		{`quote(5 + 8)`, `(5 + 8)`},
+		{`quote(foobar + barfoo)`, `(foobar + barfoo)`},
+		{`quote(unquote(4 + 4))`, `8`},
+		{`quote(8 + unquote(4 + 4))`, `(8 + 8)`},
+		{`let foobar = 8; quote(unquote(foobar))`, `8`},
+		{`quote(unquote(true == false))`, `false`},
+		{`quote(unquote("a" + "b"))`, `ab`},
+		{`quote(unquote([1, 2]))`, `[1, 2]`},
+		{`quote(unquote(quote(4 + 4)))`, `(4 + 4)`},
+		{`let q = quote(4 + 4); quote(unquote(4 + 4) + unquote(q))`, `(8 + (4 + 4))`},
+		{`let f = fn(x) { quote(unquote(x)) }; f(1); f(2)`, `2`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		quote, ok := evaluated.(*object.Quote)
+		if !ok {
+			t.Errorf("%q: expected *object.Quote. got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if quote.Node.String() != tt.expected {
+			t.Errorf("%q: quote.Node is not %q. got=%q", tt.input, tt.expected, quote.Node.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(1, 2)`, "wrong number of arguments. got=2, want=1"},
+		{`quote(unquote())`, "wrong number of arguments. got=0, want=1"},
+		{`quote(unquote(x))`, "identifier not found: x"},
+		{`quote(unquote({}))`, "argument to `unquote` must be INTEGER, BOOLEAN, STRING, ARRAY or QUOTE, got HASH"},
+	}
+
+	for _, tt := range errorTests {
+		testExpectedObject(t, testEval(tt.input), errorMessage(tt.expected))
+	}
+}
+
+func TestDefineMacros(t *testing.T) {
+	input := `
+	let number = 1;
+	let function = fn(x, y) { x + y };
+	let mymacro = macro(x, y) { x + y; };
+	`
+
+	env := object.NewEnvironment()
+	program := parser.New(lexer.New(input)).ParseProgram()
+
+	if err := DefineMacros(program, env); err != nil {
+		t.Fatalf("DefineMacros returned error: %s", err)
+	}
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2. got=%d", len(program.Statements))
+	}
+
+	if _, ok := env.Get("number"); ok {
+		t.Errorf("number should not be defined")
+	}
+
+	obj, ok := env.Get("mymacro")
+	if !ok {
+		t.Fatalf("macro not in environment")
+	}
+
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		t.Fatalf("obj is not object.Macro. got=%T (%+v)", obj, obj)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("macro.Parameters does not contain 2. got=%d", len(macro.Parameters))
+	}
+
+	if macro.Body.String() != "(x + y)" {
+		t.Errorf("macro.Body is not (x + y). got=%s", macro.Body.String())
+	}
+
+	frozen := object.NewEnvironment()
+	frozen.Freeze()
+	program = parser.New(lexer.New(input)).ParseProgram()
+
+	err := DefineMacros(program, frozen)
+	if err == nil || err.Error() != "RuntimeError: cannot bind mymacro in frozen environment" {
+		t.Errorf("DefineMacros on frozen env returned %v", err)
+	}
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let infix = macro() { quote(1 + 2); }; infix();`,
+			`(1 + 2)`,
+		},
+		{
+			`let reverse = macro(a, b) { quote(unquote(b) - unquote(a)); }; reverse(2 + 2, 10 - 5);`,
+			`(10 - 5) - (2 + 2)`,
+		},
+		{
+			`
+			let unless = macro(condition, consequence, alternative) {
+				quote(if (!(unquote(condition))) {
+					unquote(consequence);
+				} else {
+					unquote(alternative);
+				});
+			};
+
+			unless(10 > 5, puts("not greater"), puts("greater"));
+			`,
+			`if (!(10 > 5)) { puts("not greater") } else { puts("greater") }`,
+		},
+		{
+			`let double = macro(x) { quote(unquote(x) * 2); }; let f = fn() { [double(1), double(2)] };`,
+			`let f = fn() { [1 * 2, 2 * 2] };`,
+		},
+	}
+
+	for _, tt := range tests {
+		expected := parser.New(lexer.New(tt.expected)).ParseProgram()
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+
+		env := object.NewEnvironment()
+		if err := DefineMacros(program, env); err != nil {
+			t.Fatalf("DefineMacros returned error: %s", err)
+		}
+
+		expanded, err := ExpandMacros(program, env)
+		if err != nil {
+			t.Fatalf("ExpandMacros returned error: %s", err)
+		}
+
+		if expanded.String() != expected.String() {
+			t.Errorf("not equal. want=%q, got=%q", expected.String(), expanded.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`let m = macro(x) { x }; m()`, "ArgumentError: wrong number of arguments. got=0, want=1"},
+		{`let m = macro(x) { 1 }; m(2)`, "TypeError: macro m must return QUOTE, got INTEGER"},
+		{`let m = macro(x) { }; m(2)`, "TypeError: macro m must return QUOTE, got NULL"},
+		{`let m = macro(x) { throw "boom" }; m(2)`, "Error: boom"},
+	}
+
+	for _, tt := range errorTests {
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+
+		env := object.NewEnvironment()
+		if err := DefineMacros(program, env); err != nil {
+			t.Fatalf("DefineMacros returned error: %s", err)
+		}
+
+		if _, err := ExpandMacros(program, env); err == nil || err.Error() != tt.expected {
+			t.Errorf("%q: error is not %q. got=%v", tt.input, tt.expected, err)
+		}
+	}
+
+	evaluated := testEval(`let m = fn() { macro(x) { x } }; m()`)
+	testExpectedObject(t, evaluated, errorMessage("macro must be defined by top-level let"))
+}
+
+func TestWrap(t *testing.T) {
+	env := object.NewEnvironment()
+	wrap := func(name string, fn interface{}) {
+		b, err := Wrap(fn)
+		if err != nil {
+			t.Fatalf("Wrap(%s) returned error: %s", name, err)
+		}
+		env.Set(name, b)
+	}
+
+	wrap("check", func(name string, age int64) (bool, error) {
+		if age < 0 {
+			return false, fmt.Errorf("negative age of %s", name)
+		}
+		return age >= 20, nil
+	})
+	wrap("total", func(values ...int) int {
+		sum := 0
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	})
+	wrap("tags", func(m map[string][]string) []string {
+		tags := []string{}
+		for _, k := range []string{"a", "b"} {
+			tags = append(tags, m[k]...)
+		}
+		return tags
+	})
+	wrap("identity", func(obj object.Object) object.Object { return obj })
+	wrap("nothing", func() {})
+	wrap("small", func(n int8) int8 { return n })
+	wrap("explode", func() int { panic("boom") })
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`check("bob", 30)`, true},
+		{`check("bob", 10)`, false},
+		{`check("bob", -1)`, errorMessage("negative age of bob")},
+		{`try { check("bob", -1) } catch (e) { e["kind"] }`, "RuntimeError"},
+		{`check(30, "bob")`, errorMessage("argument 1 must be STRING, got INTEGER")},
+		{`check("bob")`, errorMessage("wrong number of arguments. got=1, want=2")},
+		{`total()`, 0},
+		{`total(1, 2, 3)`, 6},
+		{`total(1, "2")`, errorMessage("argument 2 must be INTEGER, got STRING")},
+		{`tags({"a": ["x"], "b": ["y", "z"]})`, []interface{}{"x", "y", "z"}},
+		{`tags({"a": [1]})`, errorMessage(`argument 1 at ["a"][0] must be STRING, got INTEGER`)},
+		{`identity([1, true])`, []interface{}{1, true}},
+		{`nothing()`, nil},
+		{`small(300)`, errorMessage("argument 1 overflows int8, got 300")},
+		{`explode()`, errorMessage("panic: boom")},
+	}
+
+	for _, tt := range tests {
+		obj := testEvalWithEnv(tt.input, env)
+		testExpectedObject(t, obj, tt.expected)
+	}
+}
+
+func TestWrapErrors(t *testing.T) {
+	tests := []struct {
+		fn       interface{}
+		expected string
+	}{
+		{1, "wrap: int is not func"},
+		{func(f float64) {}, "wrap: unsupported parameter type float64"},
+		{func() (int, int) { return 0, 0 }, "wrap: too many results of func() (int, int)"},
+		{func() chan int { return nil }, "wrap: unsupported result type chan int"},
+		{func(m map[string]float64) {}, "wrap: unsupported parameter type map[string]float64"},
+	}
+
+	for _, tt := range tests {
+		_, err := Wrap(tt.fn)
+		if err == nil {
+			t.Errorf("Wrap(%T) does not return error", tt.fn)
+			continue
+		}
+
+		if err.Error() != tt.expected {
+			t.Errorf("error is not %q. got=%q", tt.expected, err.Error())
+		}
+	}
+}
+
+func TestRegisterFunc(t *testing.T) {
+	env := object.NewEnvironment()
+	err := RegisterFunc(env, "test_greet", func(name string) string {
+		return "hello, " + name
+	})
+	if err != nil {
+		t.Fatalf("RegisterFunc returned error: %s", err)
+	}
+
+	testStringObject(t, testEvalWithEnv(`test_greet("monkey")`, env), "hello, monkey")
+	testStringObject(t, testEvalWithEnv(`let f = fn() { test_greet("f") }; f()`, env), "hello, f")
+
+	// registered builtin is not visible from other environments
+	testExpectedObject(t, testEval(`test_greet("monkey")`), errorMessage("identifier not found: test_greet"))
+
+	// core builtin is shadowed only in env
+	if err := RegisterFunc(env, "len", func(s string) int64 { return -1 }); err != nil {
+		t.Fatalf("RegisterFunc returned error: %s", err)
+	}
+	testIntegerObject(t, testEvalWithEnv(`len("abc")`, env), -1)
+	testIntegerObject(t, testEval(`len("abc")`), 3)
+
+	if err := RegisterFunc(env, "test_bad", "x"); err == nil {
+		t.Errorf("RegisterFunc does not return error for non-func")
+	}
+}
+
+// TestConcurrentEval is meant to be run with -race
+func TestConcurrentEval(t *testing.T) {
+	base := object.NewEnvironment()
+	testEvalWithEnv(`
+let counter = fn(start) { fn(step) { start + step } };
+let total = fn(arr) { reduce(arr, fn(acc, x) { acc + x }, 0) };
+`, base)
+	base.Freeze()
+
+	if err := testEvalWithEnv(`let x = 1`, base); !isError(err) {
+		t.Errorf("let in frozen environment is not error. got=%v", err)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]object.Object, 32)
+
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			env := object.NewEnclosedEnvironment(base)
+			env.Set("i", &object.Integer{Value: int64(i)})
+			results[i] = testEvalWithEnv(`let c = counter(i); total(map(range(10), c))`, env)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, obj := range results {
+		testIntegerObject(t, obj, int64(45+10*i))
+	}
+}
+
+func TestFunctionInheritsCallerValues(t *testing.T) {
+	base := object.NewEnvironment()
+	testEvalWithEnv(`let say = fn(s) { puts(s) };`, base)
+
+	var out bytes.Buffer
+	env := object.NewEnclosedEnvironment(base)
+	SetOutput(env, &out)
+
+	testEvalWithEnv(`say("hi")`, env)
+
+	if out.String() != "hi\n" {
+		t.Errorf("output is not written to writer of caller. got=%q", out.String())
+	}
+}
+
+func TestConcurrencyBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`await(spawn(fn(a, b) { a + b }, 1, 2))`, 3},
+		{`let t = spawn(fn() { 1 }); await(t); await(t)`, 1},
+		{`await(spawn(len, "abc"))`, 3},
+		{`let tasks = map(range(5), fn(i) { spawn(fn() { i * i }) }); map(tasks, await)`, []interface{}{0, 1, 4, 9, 16}},
+		{`await(spawn(fn() { throw "oops" }))`, errorMessage("oops")},
+		{`try { await(spawn(fn() { 1 / 0 })) } catch (e) { e["message"] }`, "division by zero"},
+		{`let ch = channel(); spawn(fn() { send(ch, "ping") }); recv(ch)`, "ping"},
+		{`let ch = channel(2); send(ch, 1); send(ch, 2); close(ch); [recv(ch), recv(ch), recv(ch)]`, []interface{}{1, 2, nil}},
+		{`
+let ch = channel();
+let producer = fn(n) { if (n < 3) { send(ch, n); producer(n + 1) } else { close(ch) } };
+spawn(producer, 0);
+let consume = fn(acc) { let v = recv(ch); if (is_null(v)) { acc } else { consume(push(acc, v)) } };
+consume([])`, []interface{}{0, 1, 2}},
+		{`let a = channel(); let b = channel(1); send(b, "b"); select([a, b])`, []interface{}{1, "b"}},
+		{`let a = channel(); close(a); select([a])`, []interface{}{0, nil}},
+		{`let ch = channel(); close(ch); close(ch)`, errorMessage("close: close of closed channel")},
+		{`let ch = channel(); close(ch); send(ch, 1)`, errorMessage("send: send on closed channel")},
+		{`recv(channel())`, errorMessage("recv: deadlock, all tasks are blocked")},
+		{`send(channel(), 1)`, errorMessage("send: deadlock, all tasks are blocked")},
+		{`let ch = channel(); try { await(spawn(fn() { recv(ch) })) } catch (e) { contains(e["message"], "deadlock") }`, true},
+		{`let ch = channel(); try { recv(ch) } catch (e) { e["message"] }`, "recv: deadlock, all tasks are blocked"},
+		{`type(channel())`, "CHANNEL"},
+		{`type(spawn(fn() { 1 }))`, "TASK"},
+		{`spawn(1)`, errorMessage("argument to `spawn` must be FUNCTION, got INTEGER")},
+		{`await(1)`, errorMessage("argument to `await` must be TASK, got INTEGER")},
+		{`select([1])`, errorMessage("argument to `select` must be ARRAY of CHANNEL, got INTEGER")},
+		{`channel(-1)`, errorMessage("argument to `channel` must not be negative, got -1")},
+		{`channel(9223372036854775807)`, errorMessage("argument to `channel` must not exceed 1048576, got 9223372036854775807")},
+	}
+
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+		testExpectedObject(t, obj, tt.expected)
+	}
+}
+
+func TestBlockingStopsWithContext(t *testing.T) {
+	env := object.NewEnvironment()
+	ctx, cancel := context.WithCancel(context.Background())
+	SetContext(env, ctx)
+	cancel()
+
+	obj := testEvalWithEnv(`let ch = channel(); spawn(fn() { 1 }); recv(ch)`, env)
+	testExpectedObject(t, obj, errorMessage("evaluation stopped: context canceled"))
+}
+
+func TestHashInspect(t *testing.T) {
+	obj := testEval(`{"b": 1, "a": [2], 3: "c"}`)
+
+	expected := `{3: c, a: [2], b: 1}`
+	if obj.Inspect() != expected {
+		t.Errorf("obj.Inspect() is not %s. got=%s", expected, obj.Inspect())
+	}
+}
+
+func TestOutputBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`puts("hello", 1, [1, 2])`, "hello\n1\n[1, 2]\n"},
+		{`puts()`, ""},
+		{`print("a", 1); print("b")`, "a1b"},
+		{`let f = fn(x) { puts(x) }; map([1, 2], f)`, "1\n2\n"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		env := object.NewEnvironment()
+		SetOutput(env, &out)
+
+		obj := testEvalWithEnv(tt.input, env)
+		if isError(obj) {
+			t.Errorf("obj is object.Error. got=%s", obj.Inspect())
+			continue
+		}
+
+		if out.String() != tt.expected {
+			t.Errorf("output is not %q. got=%q", tt.expected, out.String())
+		}
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	env := object.NewEnvironment()
+	SetInput(env, strings.NewReader("first\r\nsecond\nlast"))
+
+	tests := []interface{}{"first", "second", "last", nil, nil}
+	for _, expected := range tests {
+		obj := testEvalWithEnv(`read_line()`, env)
+		testExpectedObject(t, obj, expected)
+	}
+
+	obj := testEvalWithEnv(`read_line(1)`, env)
+	testExpectedObject(t, obj, errorMessage("wrong number of arguments. got=1, want=0"))
+}
+
+func TestSeededRandom(t *testing.T) {
+	input := `[random(), random_int(1, 6), shuffle(range(10)), choice(["a", "b", "c"])]`
+
+	first := object.NewEnvironment()
+	SetSeed(first, 42)
+	second := object.NewEnvironment()
+	SetSeed(second, 42)
+
+	for i := 0; i < 3; i++ {
+		a := testEvalWithEnv(input, first)
+		b := testEvalWithEnv(input, second)
+
+		if a.Inspect() != b.Inspect() {
+			t.Errorf("same seed gives different results. got=%s and %s", a.Inspect(), b.Inspect())
+		}
+	}
+}
+
+func TestRandomBuiltins(t *testing.T) {
+	env := object.NewEnvironment()
+	SetSeed(env, 1)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`random() < 0`, false},
+		{`let n = random_int(3, 5); if (n < 3) { false } else { n < 6 }`, true},
+		{`random_int(7, 7)`, 7},
+		{`sort(shuffle(range(5)))`, []interface{}{0, 1, 2, 3, 4}},
+		{`let a = [1, 2, 3]; shuffle(a); a`, []interface{}{1, 2, 3}},
+		{`shuffle([])`, []interface{}{}},
+		{`contains([1, 2, 3], choice([1, 2, 3]))`, true},
+		{`random(1)`, errorMessage("wrong number of arguments. got=1, want=0")},
+		{`random_int(5, 3)`, errorMessage("argument to `random_int` has lo > hi, got 5 > 3")},
+		{`shuffle(1)`, errorMessage("argument to `shuffle` must be ARRAY, got INTEGER")},
+		{`choice([])`, errorMessage("argument to `choice` must not be empty")},
+	}
+
+	for _, tt := range tests {
+		obj := testEvalWithEnv(tt.input, env)
+		testExpectedObject(t, obj, tt.expected)
+	}
+}
+
+func TestArrayLiteral(t *testing.T) {
+	input := `[1, 2 * 2, 3 + 3]`
+	obj := testEval(input)
+
+	array, ok := obj.(*object.Array)
+	if !ok {
+		t.Errorf("obj is not object.Array. got=%T", obj)
+		return
+	}
+
+	if len(array.Elements) != 3 {
+		t.Errorf("array.Elements does not contain 3. got=%d", len(array.Elements))
+		return
+	}
+
+	if !testIntegerObject(t, array.Elements[0], 1) {
+		return
+	}
+
+	if !testIntegerObject(t, array.Elements[1], 4) {
+		return
+	}
+
+	if !testIntegerObject(t, array.Elements[2], 6) {
+		return
+	}
+}
+
+func TestArrayIndexExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			"[1, 2, 3][0]",
+			1,
+		},
+		{
+			"[1, 2, 3][1]",
+			2,
+		},
+		{
+			"[1, 2, 3][2]",
+			3,
+		},
+		{
+			"let i = 0; [1][i];",
+			1,
+		},
+		{
+			"[1, 2, 3][1 + 1]",
+			3,
+		},
+		{
+			"let myArray = [1, 2, 3]; myArray[2];",
+			3,
+		},
+		{
+			"let myArray = [1, 2, 3]; myArray[0] + myArray[1] + myArray[2];",
+			6,
+		},
+		{
+			"let myArray = [1, 2, 3]; let i = myArray[0]; myArray[i];",
+			2,
+		},
+		{
+			"[1, 2, 3][3]",
+			nil,
+		},
+		{
+			"[1, 2, 3][-1]",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, obj, int64(integer))
+		} else {
+			testNullObject(t, obj)
+		}
+	}
+}
+
+func TestParseHashLiteral(t *testing.T) {
+	input := `let two = "two";
+
+{
+ "one": 10 - 9,
+ two: 1 + 1,
+ "thr" + "ee": 6 / 2,
+ 4: 4,
+ true: 5,
+ false: 6
+}`
+	obj := testEval(input)
+	hash, ok := obj.(*object.Hash)
+	if !ok {
+		t.Errorf("obj is not object.Hash. got=%T", obj)
+		return
+	}
+
+	expected := []struct {
+		key   object.Object
+		value int64
+	}{
+		{&object.String{Value: "one"}, 1},
+		{&object.String{Value: "two"}, 2},
+		{&object.String{Value: "three"}, 3},
+		{&object.Integer{Value: 4}, 4},
+		{TRUE, 5},
+		{FALSE, 6},
+	}
+
+	if hash.Len() != 6 {
+		t.Errorf("hash.Pairs does not contain 6. got=%d", hash.Len())
+		return
+	}
+
+	for _, e := range expected {
+		value, ok := hash.Get(e.key)
+		if !ok {
+			t.Errorf("no pair for given key in Pairs")
+			continue
+		}
+
+		testIntegerObject(t, value, e.value)
+	}
+}
+
+func TestHashIndexExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`{"foo": 5}["foo"]`, 5,
+		},
+		{
+			`{"foo": 5}["bar"]`, nil,
+		},
+		{
+			`let key = "foo"; {"foo": 5}[key]`,
+			5,
+		},
+		{
+			`{}["foo"]`,
+			nil,
+		},
+		{
+			`{5: 5}[5]`,
+			5,
+		},
+		{
+			`{true: 5}[true]`,
+			5,
+		},
+		{
+			`{[1, 2]: 5}[[1, 2]]`,
+			5,
+		},
+		{
+			`{[1, 2]: 5}[[2, 1]]`,
+			nil,
+		},
+		{
+			`let x = 1; let y = 2; {[x, y]: 5}[[1, 2]]`,
+			5,
+		},
+		{
+			`{{"a": 1, "b": [2]}: 5}[{"b": [2], "a": 1}]`,
+			5,
+		},
+	}
+
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+		expectedInt, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, obj, int64(expectedInt))
+		} else {
+			testNullObject(t, obj)
+		}
+	}
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`try { 10 } catch (e) { 20 }`, 10},
+		{`try { throw "boom"; 10 } catch (e) { 20 }`, 20},
+		{`try { throw "boom"; } catch (e) { e["message"] }`, "boom"},
+		{`try { throw "boom"; } catch (e) { e["kind"] }`, "Error"},
+		{`try { throw 42; } catch (e) { e["value"] }`, 42},
+		{`try { 1 + true } catch (e) { e["kind"] }`, "TypeError"},
+		{`try { 1 + true } catch (e) { e["message"] }`, "type mismatch: INTEGER + BOOLEAN"},
+		{`try { foo } catch (e) { e["kind"] }`, "NameError"},
+		{`try { len(1) } catch (e) { e["kind"] }`, "TypeError"},
+		{`try { len(1, 2) } catch (e) { e["kind"] }`, "ArgumentError"},
+		{`try { throw {"message": "bad", "kind": "ValueError"} } catch (e) { e["kind"] }`, "ValueError"},
+		{`try { try { 1 + true } catch (e) { throw e; } } catch (e) { e["kind"] }`, "TypeError"},
+		{`let e = 1; try { throw "boom"; } catch (e) { 2 }; e`, 1},
+		{`try { 1 } finally { let y = 2; }; y`, 2},
+		{`let x = try { 1 } finally { 2 }; x`, 1},
+		{`let f = fn() { try { return 1; } catch (e) { 2 }; 3 }; f()`, 1},
+		{`let f = fn() { try { 1 } finally { return 2; } }; f()`, 2},
+		{`try { } catch (e) { 1 }`, nil},
+	}
+
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, obj, int64(expected))
+		case string:
+			testStringObject(t, obj, expected)
+		default:
+			testNullObject(t, obj)
+		}
+	}
+}
+
+func TestUncaughtError(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+		expectedKind    string
+		expectedStack   []string
+	}{
+		{
+			`throw "boom"; 5`,
+			"boom",
+			"Error",
+			nil,
+		},
+		{
+			`try { throw "a"; } finally { 1 }`,
+			"a",
+			"Error",
+			nil,
+		},
+		{
+			`try { throw "a"; } catch (e) { throw "b"; }`,
+			"b",
+			"Error",
+			nil,
+		},
+		{
+			`try { 1 } finally { throw "c"; }`,
+			"c",
+			"Error",
+			nil,
+		},
+		{
+			`let inner = fn() { 1 + true }; let outer = fn() { inner() }; outer()`,
+			"type mismatch: INTEGER + BOOLEAN",
+			"TypeError",
+			[]string{"inner", "outer"},
+		},
+		{
+			`let f = fn() { try { throw "x"; } catch (e) { throw e; } }; try { f() } catch (e) { throw e; }`,
+			"x",
+			"Error",
+			[]string{"f"},
+		},
+	}
+
+	for _, tt := range tests {
+		obj := testEval(tt.input)
+
+		e, ok := obj.(*object.Error)
+		if !ok {
+			t.Errorf("obj is not object.Error. got=%T", obj)
+			continue
+		}
+
+		if e.Message != tt.expectedMessage {
+			t.Errorf("e.Message is not %s. got=%s", tt.expectedMessage, e.Message)
+		}
+
+		if e.Kind != tt.expectedKind {
+			t.Errorf("e.Kind is not %s. got=%s", tt.expectedKind, e.Kind)
+		}
+
+		if len(e.Stack) != len(tt.expectedStack) {
+			t.Errorf("e.Stack is not %v. got=%v", tt.expectedStack, e.Stack)
+			continue
+		}
+
+		for i, frame := range tt.expectedStack {
+			if e.Stack[i] != frame {
+				t.Errorf("e.Stack[%d] is not %s. got=%s", i, frame, e.Stack[i])
+			}
+		}
+	}
+}
+
+// TestAwaitFailedTask should be run w/ -race
+func TestAwaitFailedTask(t *testing.T) {
+	input := `
+let failing = spawn(fn() { throw "boom" });
+let stack = fn() { try { await(failing) } catch (e) { e["stack"] } };
+let tasks = map(range(8), fn(i) { spawn(stack) });
+push(map(tasks, await), stack())
+`
+	obj := testEval(input)
+
+	expected := []interface{}{}
+	for i := 0; i < 9; i++ {
+		expected = append(expected, []interface{}{"await"})
+	}
+	testExpectedObject(t, obj, expected)
+}
+
+func TestErrorIsNotModified(t *testing.T) {
+	shared := &object.Error{Message: "shared", Kind: object.RUNTIME_ERROR}
+
+	env := object.NewEnvironment()
+	env.Set("fail", &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return shared
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		obj := testEvalWithEnv(`let f = fn() { fail() }; f()`, env)
+
+		e, ok := obj.(*object.Error)
+		if !ok {
+			t.Fatalf("obj is not object.Error. got=%T", obj)
+		}
+
+		if len(e.Stack) != 2 || e.Stack[0] != "fail" || e.Stack[1] != "f" {
+			t.Errorf("e.Stack is not [fail f]. got=%v", e.Stack)
+		}
+	}
+
+	if len(shared.Stack) != 0 {
+		t.Errorf("shared error is modified. got=%v", shared.Stack)
+	}
+}
+
+func testEval(input string) object.Object {
+	return testEvalWithEnv(input, object.NewEnvironment())
+}
+
+func testEvalWithEnv(input string, env *object.Environment) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	return Eval(program, env)
+}
+
+func testNullObject(t *testing.T, obj object.Object) bool {
+	if obj != NULL {
+		t.Errorf("obj is not NULL. got=%T", obj)
+		return false
+	}
+
+	return true
+}
+
+func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
+	intObj, ok := obj.(*object.Integer)
+	if !ok {
+		t.Errorf("obj is not object.Integer. got=%T", obj)
+		return false
+	}
+
+	if intObj.Value != expected {
+		t.Errorf("intObj.Value is not %d. got=%d", expected, intObj.Value)
+		return false
+	}
+
+	return true
+}
+
+func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
+	boolObj, ok := obj.(*object.Boolean)
+	if !ok {
+		t.Errorf("obj is not object.Boolean. got=%T", obj)
+		return false
+	}
+
+	if boolObj.Value != expected {
+		t.Errorf("boolObj.Value is not %t. got=%t", expected, boolObj.Value)
+		return false
+	}
+
+	return true
+
+}
+
+func testStringObject(t *testing.T, obj object.Object, expected string) bool {
+	stringObj, ok := obj.(*object.String)
+	if !ok {
+		t.Errorf("obj is not object.String. got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if stringObj.Value != expected {
+		t.Errorf("stringObj.Value is not %s. got=%s", expected, stringObj.Value)
+		return false
 	}
 
 	return true
+}
+
+// errorMessage is expected message of object.Error
+type errorMessage string
+
+// testExpectedObject check obj by type of expected,
+// []interface{} is expected elements of object.Array and nil is NULL.
+func testExpectedObject(t *testing.T, obj object.Object, expected interface{}) bool {
+	switch expected := expected.(type) {
+	case int:
+		return testIntegerObject(t, obj, int64(expected))
+	case bool:
+		return testBooleanObject(t, obj, expected)
+	case string:
+		return testStringObject(t, obj, expected)
+	case errorMessage:
+		errObj, ok := obj.(*object.Error)
+		if !ok {
+			t.Errorf("obj is not object.Error. got=%T (%+v)", obj, obj)
+			return false
+		}
+
+		if errObj.Message != string(expected) {
+			t.Errorf("errObj.Message is not %s. got=%s", expected, errObj.Message)
+			return false
+		}
+
+		return true
+	case []interface{}:
+		array, ok := obj.(*object.Array)
+		if !ok {
+			t.Errorf("obj is not object.Array. got=%T (%+v)", obj, obj)
+			return false
+		}
+
+		if len(array.Elements) != len(expected) {
+			t.Errorf("array.Elements does not contain %d. got=%d (%s)",
+				len(expected), len(array.Elements), array.Inspect())
+			return false
+		}
+
+		for i, e := range expected {
+			if !testExpectedObject(t, array.Elements[i], e) {
+				return false
+			}
+		}
+
+		return true
+	case nil:
+		return testNullObject(t, obj)
+	}
 
+	t.Errorf("type of expected not handled. got=%T", expected)
+	return false
 }
@@ -0,0 +1,90 @@
+package evaluator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+type fileSystemKey struct{}
+
+// FileSystem is jail of file builtins, paths are resolved relative to Root
+// and never escape it even through symlinks
+type FileSystem struct {
+	Root  string
+	Read  bool
+	Write bool
+}
+
+var errPathEscapesRoot = errors.New("path escapes root")
+
+// SetFileSystem enable file builtins on env, they are disabled if not set
+func SetFileSystem(env *object.Environment, fs *FileSystem) {
+	env.SetValue(fileSystemKey{}, fs)
+}
+
+// fileSystem return FileSystem set by SetFileSystem, nil if not set
+func fileSystem(env *object.Environment) *FileSystem {
+	fs, _ := env.Value(fileSystemKey{}).(*FileSystem)
+	return fs
+}
+
+// resolve return host path of path, error if it is outside of Root
+// or goes through dangling symlink
+func (fs *FileSystem) resolve(path string) (string, error) {
+	root, err := filepath.Abs(fs.Root)
+	if err != nil {
+		return "", err
+	}
+
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+
+	full := filepath.Join(root, path)
+	if !isWithin(root, full) {
+		return "", errPathEscapesRoot
+	}
+
+	// follow symlinks of the longest existing part of path
+	existing := full
+	for {
+		real, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			if !isWithin(root, real) {
+				return "", errPathEscapesRoot
+			}
+			break
+		}
+
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		// dangling symlink would be followed when file is created
+		if info, err := os.Lstat(existing); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return "", errPathEscapesRoot
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	return full, nil
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,77 @@
+package evaluator
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+type outputKey struct{}
+
+type inputKey struct{}
+
+var stdin = &lockedReader{
+	r: bufio.NewReader(os.Stdin),
+}
+
+// SetOutput set writer of puts and print on env,
+// writes are serialized since spawned tasks share it
+func SetOutput(env *object.Environment, w io.Writer) {
+	env.SetValue(outputKey{}, &lockedWriter{w: w})
+}
+
+// SetInput set reader of read_line on env,
+// reads are serialized since spawned tasks share it
+func SetInput(env *object.Environment, r io.Reader) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	env.SetValue(inputKey{}, &lockedReader{r: br})
+}
+
+// output return writer set by SetOutput, os.Stdout if not set
+func output(env *object.Environment) io.Writer {
+	if w, ok := env.Value(outputKey{}).(*lockedWriter); ok {
+		return w
+	}
+
+	return os.Stdout
+}
+
+// input return reader set by SetInput, os.Stdin if not set
+func input(env *object.Environment) *lockedReader {
+	if r, ok := env.Value(inputKey{}).(*lockedReader); ok {
+		return r
+	}
+
+	return stdin
+}
+
+// lockedWriter is io.Writer safe for concurrent use
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// lockedReader is bufio.Reader safe for concurrent use
+type lockedReader struct {
+	mu sync.Mutex
+	r  *bufio.Reader
+}
+
+func (r *lockedReader) ReadString(delim byte) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.ReadString(delim)
+}
@@ -0,0 +1,43 @@
+package evaluator
+
+import (
+	"context"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+type contextKey struct{}
+
+type maxCallDepthKey struct{}
+
+// maxLength is maximum length of array or string built by builtins,
+// so that script can not exhaust memory of host by single call
+const maxLength = 1 << 24
+
+// SetContext set context on env, evaluation stops when ctx is done.
+// nil ctx hides context of outer Env.
+func SetContext(env *object.Environment, ctx context.Context) {
+	env.SetValue(contextKey{}, ctx)
+}
+
+// SetMaxCallDepth limit depth of nested function calls on env, 0 is unlimited
+func SetMaxCallDepth(env *object.Environment, depth int) {
+	env.SetValue(maxCallDepthKey{}, depth)
+}
+
+// checkLimits return LimitError if caller in env can not call function anymore
+func checkLimits(env *object.Environment) *object.Error {
+	if ctx, ok := env.Value(contextKey{}).(context.Context); ok {
+		if err := ctx.Err(); err != nil {
+			return newKindError(object.LIMIT_ERROR, "evaluation stopped: %s", err)
+		}
+	}
+
+	if max, ok := env.Value(maxCallDepthKey{}).(int); ok && max > 0 {
+		if env.CallDepth() >= max {
+			return newKindError(object.LIMIT_ERROR, "maximum call depth %d exceeded", max)
+		}
+	}
+
+	return nil
+}
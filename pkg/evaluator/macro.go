@@ -0,0 +1,111 @@
+package evaluator
+
+import (
+	"github.com/naoto0822/monkey-interpreter/pkg/ast"
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+// DefineMacros bind macros defined by top-level let in env,
+// and remove the definitions from program
+func DefineMacros(program *ast.Program, env *object.Environment) error {
+	statements := []ast.Statement{}
+
+	for _, stmt := range program.Statements {
+		let, ok := stmt.(*ast.LetStatement)
+		if !ok {
+			statements = append(statements, stmt)
+			continue
+		}
+
+		literal, ok := let.Value.(*ast.MacroLiteral)
+		if !ok {
+			statements = append(statements, stmt)
+			continue
+		}
+
+		if env.IsFrozen() {
+			return newError("cannot bind %s in frozen environment", let.Name.Value)
+		}
+
+		env.Set(let.Name.Value, &object.Macro{
+			Parameters: literal.Parameters,
+			Body:       literal.Body,
+			Env:        env,
+		})
+	}
+
+	program.Statements = statements
+	return nil
+}
+
+// ExpandMacros return copy of program whose calls of macros in env are
+// replaced w/ code quoted by the macros. program is not changed.
+func ExpandMacros(program ast.Node, env *object.Environment) (ast.Node, error) {
+	var err *object.Error
+
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		call, ok := node.(*ast.CallExpression)
+		if err != nil || !ok {
+			return node
+		}
+
+		macro, ok := macroOf(call, env)
+		if !ok {
+			return node
+		}
+
+		if len(call.Arguments) < len(macro.Parameters) {
+			err = newArgumentError("wrong number of arguments. got=%d, want=%d",
+				len(call.Arguments), len(macro.Parameters))
+			return node
+		}
+
+		evaluated := unwrapReturnValue(Eval(macro.Body, extendMacroEnv(macro, call.Arguments)))
+		if e, ok := evaluated.(*object.Error); ok {
+			err = e
+			return node
+		}
+
+		quote, ok := evaluated.(*object.Quote)
+		if !ok {
+			err = newTypeError("macro %s must return QUOTE, got %s",
+				call.Function.String(), objectType(evaluated))
+			return node
+		}
+
+		return quote.Node
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expanded, nil
+}
+
+// macroOf return macro called by call, ok is false if it is not macro call
+func macroOf(call *ast.CallExpression, env *object.Environment) (*object.Macro, bool) {
+	ident, ok := call.Function.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	obj, ok := env.Get(ident.Value)
+	if !ok {
+		return nil, false
+	}
+
+	macro, ok := obj.(*object.Macro)
+	return macro, ok
+}
+
+// extendMacroEnv gen Env of macro, arguments are bound as Quote
+func extendMacroEnv(macro *object.Macro, args []ast.Expression) *object.Environment {
+	env := object.NewEnclosedEnvironment(macro.Env)
+
+	for i, p := range macro.Parameters {
+		env.Set(p.Value, &object.Quote{Node: args[i]})
+	}
+
+	return env
+}
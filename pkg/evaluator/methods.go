@@ -0,0 +1,146 @@
+package evaluator
+
+import (
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+// methods is method name and builtin name of each receiver type,
+// receiver is passed to builtin as first argument
+var methods = map[object.Type]map[string]string{
+	object.STRING_OBJ: {
+		"len":         "len",
+		"split":       "split",
+		"trim":        "trim",
+		"upper":       "upper",
+		"lower":       "lower",
+		"replace":     "replace",
+		"starts_with": "starts_with",
+		"ends_with":   "ends_with",
+		"repeat":      "repeat",
+		"chars":       "chars",
+		"pad_left":    "pad_left",
+		"pad_right":   "pad_right",
+		"format":      "format",
+		"contains":    "contains",
+		"int":         "int",
+	},
+	object.ARRAY_OBJ: {
+		"len":      "len",
+		"first":    "first",
+		"last":     "last",
+		"rest":     "rest",
+		"push":     "push",
+		"map":      "map",
+		"filter":   "filter",
+		"reduce":   "reduce",
+		"sort":     "sort",
+		"reverse":  "reverse",
+		"contains": "contains",
+		"index_of": "index_of",
+		"concat":   "concat",
+		"flatten":  "flatten",
+		"zip":      "zip",
+		"join":     "join",
+		"sum":      "sum",
+		"min":      "min",
+		"max":      "max",
+		"shuffle":  "shuffle",
+		"choice":   "choice",
+	},
+	object.HASH_OBJ: {
+		"len":    "len",
+		"keys":   "keys",
+		"values": "values",
+		"items":  "items",
+		"has":    "has",
+		"get":    "get",
+		"set":    "set",
+		"delete": "delete",
+		"merge":  "merge",
+	},
+	object.INTEGER_OBJ: {
+		"abs":   "abs",
+		"pow":   "pow",
+		"sqrt":  "sqrt",
+		"clamp": "clamp",
+		"gcd":   "gcd",
+	},
+	object.REGEX_OBJ: {
+		"match":    "match",
+		"find_all": "find_all",
+		"replace":  "replace_re",
+		"split":    "split_re",
+	},
+	object.TIME_OBJ: {
+		"format": "time_format",
+		"unix":   "time_unix",
+	},
+	object.TASK_OBJ: {
+		"await": "await",
+	},
+	object.CHANNEL_OBJ: {
+		"send":  "send",
+		"recv":  "recv",
+		"close": "close",
+	},
+}
+
+// commonMethods is methods of every type
+var commonMethods = map[string]string{
+	"type": "type",
+	"str":  "str",
+}
+
+// evalDotExpression return field of hash or struct, or export of module,
+// otherwise method of left bound to it
+func evalDotExpression(left object.Object, name string) object.Object {
+	switch left := left.(type) {
+	case *object.Hash:
+		// keys take precedence over methods
+		if value, ok := left.Get(&object.String{Value: name}); ok {
+			return value
+		}
+	case *object.Module:
+		return evalModuleIndexExpression(left, &object.String{Value: name})
+	case *object.Struct:
+		if value, ok := left.Get(name); ok {
+			return value
+		}
+	}
+
+	if method, ok := lookupMethod(left, name); ok {
+		return method
+	}
+
+	switch left := left.(type) {
+	case *object.Hash:
+		return NULL
+	case *object.Struct:
+		return newNameError("%s has no field %s", left.StructType.Name, name)
+	}
+
+	return newNameError("%s has no method %s", left.Type(), name)
+}
+
+// lookupMethod return builtin of method name bound to receiver
+func lookupMethod(receiver object.Object, name string) (*object.Builtin, bool) {
+	builtinName, ok := methods[receiver.Type()][name]
+	if !ok {
+		builtinName, ok = commonMethods[name]
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	builtin, ok := lookupBuiltin(builtinName)
+	if !ok {
+		return nil, false
+	}
+
+	return &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) object.Object {
+			return builtin.Fn(env, append([]object.Object{receiver}, args...)...)
+		},
+	}, true
+}
@@ -0,0 +1,290 @@
+package evaluator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/ast"
+	"github.com/naoto0822/monkey-interpreter/pkg/lexer"
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+	"github.com/naoto0822/monkey-interpreter/pkg/parser"
+	"github.com/naoto0822/monkey-interpreter/pkg/token"
+)
+
+type loaderKey struct{}
+
+type importChainKey struct{}
+
+// moduleExt is extension of module files, it can be omitted in import
+const moduleExt = ".mk"
+
+// loader find module files in dirs and evaluate each of them once
+type loader struct {
+	dirs []string
+
+	mu      sync.Mutex
+	modules map[string]*moduleEntry
+}
+
+// moduleEntry is module loaded from a file,
+// module or err is set before done is closed
+type moduleEntry struct {
+	done   chan struct{}
+	module *object.Module
+	err    *object.Error
+
+	// path is path of first import, next is entry which evaluation of this
+	// entry waits for. next is guarded by mu of loader
+	path string
+	next *moduleEntry
+}
+
+// importLink is a module being imported, file is resolved path of path
+type importLink struct {
+	file string
+	path string
+}
+
+// SetImportPath set directories to search modules of import on env,
+// modules are cached on env so that each of them is evaluated once
+func SetImportPath(env *object.Environment, dirs ...string) {
+	env.SetValue(loaderKey{}, &loader{
+		dirs:    dirs,
+		modules: make(map[string]*moduleEntry),
+	})
+}
+
+func evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	name := moduleName(node)
+	if name == "" {
+		return newValueError("import %q: module name is not identifier, use import %q as name", node.Path, node.Path)
+	}
+
+	l, ok := env.Value(loaderKey{}).(*loader)
+	if !ok {
+		return newError("import %q: import path is not set", node.Path)
+	}
+
+	module, err := l.load(node.Path, env)
+	if err != nil {
+		return err
+	}
+
+	if env.IsFrozen() {
+		return newError("cannot bind %s in frozen environment", name)
+	}
+
+	env.Set(name, module)
+
+	return nil
+}
+
+// moduleName return name to bind module, it is empty if file name
+// can not be identifier
+func moduleName(node *ast.ImportStatement) string {
+	name := node.Name()
+	if node.Alias != nil {
+		return name
+	}
+
+	if name == "" || token.LookupIdent(name) != token.IDENT {
+		return ""
+	}
+
+	for _, ch := range name {
+		if !('a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_') {
+			return ""
+		}
+	}
+
+	return name
+}
+
+// load return module of path, it is evaluated on first import
+func (l *loader) load(path string, env *object.Environment) (*object.Module, *object.Error) {
+	file, ok := l.find(path)
+	if !ok {
+		return nil, newError("import %q: module not found", path)
+	}
+
+	chain, _ := env.Value(importChainKey{}).([]importLink)
+	for i, link := range chain {
+		if link.file == file {
+			paths := make([]string, 0, len(chain)-i+1)
+			for _, link := range chain[i:] {
+				paths = append(paths, link.path)
+			}
+			paths = append(paths, path)
+
+			return nil, newError("import cycle: %s", strings.Join(paths, " -> "))
+		}
+	}
+
+	// entry of module importing path, it is nil for import of program
+	var current *moduleEntry
+
+	l.mu.Lock()
+	entry, loaded := l.modules[file]
+	if !loaded {
+		entry = &moduleEntry{
+			done: make(chan struct{}),
+			path: path,
+		}
+		l.modules[file] = entry
+	}
+
+	if len(chain) > 0 {
+		current = l.modules[chain[len(chain)-1].file]
+	}
+
+	if current != nil {
+		// entry may be evaluated by other task waiting for current
+		if paths := waitCycle(current, entry); paths != nil {
+			l.mu.Unlock()
+			return nil, newError("import cycle: %s", strings.Join(paths, " -> "))
+		}
+		current.next = entry
+	}
+	l.mu.Unlock()
+
+	if !loaded {
+		link := importLink{file: file, path: path}
+		entry.module, entry.err = evalModule(link, chain, env)
+
+		// LimitError is caused by limits of importer, not by module,
+		// so that module is evaluated again by next import
+		if entry.err != nil && entry.err.Kind == object.LIMIT_ERROR {
+			l.mu.Lock()
+			if l.modules[file] == entry {
+				delete(l.modules, file)
+			}
+			l.mu.Unlock()
+		}
+		close(entry.done)
+	}
+
+	<-entry.done
+
+	if current != nil {
+		l.mu.Lock()
+		current.next = nil
+		l.mu.Unlock()
+	}
+
+	if entry.err != nil {
+		if loaded && entry.err.Kind == object.LIMIT_ERROR {
+			return l.load(path, env)
+		}
+
+		// copy error since stack is appended while it is returned
+		return nil, entry.err.Copy()
+	}
+
+	return entry.module, nil
+}
+
+// waitCycle return paths of cycle if current waits for entry which waits
+// for current, otherwise nil. mu of loader must be held
+func waitCycle(current, entry *moduleEntry) []string {
+	paths := []string{current.path}
+
+	for e := entry; e != nil; e = e.next {
+		paths = append(paths, e.path)
+		if e == current {
+			return paths
+		}
+	}
+
+	return nil
+}
+
+// find return absolute path of module file in dirs,
+// path can not go out of dir by ..
+func (l *loader) find(path string) (string, bool) {
+	if filepath.Ext(path) == "" {
+		path += moduleExt
+	}
+
+	for _, dir := range l.dirs {
+		root, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+
+		file := filepath.Join(root, path)
+		if !isWithin(root, file) {
+			continue
+		}
+
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file, true
+		}
+	}
+
+	return "", false
+}
+
+// evalModule evaluate module file in its own Env,
+// host values such as output are inherited from importer
+func evalModule(link importLink, chain []importLink, importer *object.Environment) (*object.Module, *object.Error) {
+	src, err := ioutil.ReadFile(link.file)
+	if err != nil {
+		return nil, newError("import %q: %s", link.path, err)
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		return nil, newError("import %q: %s", link.path, strings.Join(p.Errors(), ", "))
+	}
+
+	env := object.NewFunctionEnvironment(nil, importer)
+	env.SetValue(importChainKey{}, append(chain[:len(chain):len(chain)], link))
+
+	if err := DefineMacros(program, env); err != nil {
+		return nil, err.(*object.Error)
+	}
+
+	expanded, err := ExpandMacros(program, env)
+	if err != nil {
+		return nil, err.(*object.Error)
+	}
+
+	// module shares scheduler of importer
+	if err, ok := evalStatements(expanded.(*ast.Program).Statements, env).(*object.Error); ok {
+		return nil, err
+	}
+
+	module := &object.Module{
+		Name:    strings.TrimSuffix(filepath.Base(link.file), moduleExt),
+		Path:    link.path,
+		Exports: make(map[string]object.Object),
+	}
+
+	for _, stmt := range expanded.(*ast.Program).Statements {
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			name := export.Statement.Name.Value
+			module.Exports[name], _ = env.Get(name)
+		}
+	}
+
+	return module, nil
+}
+
+func evalModuleIndexExpression(left, index object.Object) object.Object {
+	module := left.(*object.Module)
+
+	key, ok := index.(*object.String)
+	if !ok {
+		return newTypeError("module index must be STRING, got %s", index.Type())
+	}
+
+	if value, ok := module.Exports[key.Value]; ok {
+		return value
+	}
+
+	return newNameError("module %s has no export %s", module.Name, key.Value)
+}
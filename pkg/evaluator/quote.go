@@ -0,0 +1,110 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/ast"
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+	"github.com/naoto0822/monkey-interpreter/pkg/token"
+)
+
+// quote return node w/o evaluating it,
+// unquote(exp) in node is replaced w/ value of exp
+func quote(node ast.Node, env *object.Environment) object.Object {
+	var err *object.Error
+
+	node = ast.Modify(node, func(node ast.Node) ast.Node {
+		call, ok := node.(*ast.CallExpression)
+		if err != nil || !ok || !isCallOf(call, "unquote") {
+			return node
+		}
+
+		if len(call.Arguments) != 1 {
+			err = newArgumentError("wrong number of arguments. got=%d, want=1",
+				len(call.Arguments))
+			return node
+		}
+
+		value := Eval(call.Arguments[0], env)
+		if e, ok := value.(*object.Error); ok {
+			err = e
+			return node
+		}
+
+		converted, ok := objectToASTNode(value)
+		if !ok {
+			err = newTypeError("argument to `unquote` must be INTEGER, BOOLEAN, STRING, ARRAY or QUOTE, got %s",
+				objectType(value))
+			return node
+		}
+
+		return converted
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return &object.Quote{
+		Node: node,
+	}
+}
+
+func isCallOf(call *ast.CallExpression, name string) bool {
+	ident, ok := call.Function.(*ast.Identifier)
+	return ok && ident.Value == name
+}
+
+// objectToASTNode return literal of obj, ok is false if obj has no literal
+func objectToASTNode(obj object.Object) (ast.Expression, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return &ast.IntegerLiteral{
+			Token: token.Token{Type: token.INT, Literal: fmt.Sprintf("%d", obj.Value)},
+			Value: obj.Value,
+		}, true
+	case *object.Boolean:
+		t := token.Token{Type: token.FALSE, Literal: "false"}
+		if obj.Value {
+			t = token.Token{Type: token.TRUE, Literal: "true"}
+		}
+
+		return &ast.Boolean{
+			Token: t,
+			Value: obj.Value,
+		}, true
+	case *object.String:
+		return &ast.StringLiteral{
+			Token: token.Token{Type: token.STRING, Literal: obj.Value},
+			Value: obj.Value,
+		}, true
+	case *object.Array:
+		elements := make([]ast.Expression, 0, len(obj.Elements))
+		for _, e := range obj.Elements {
+			element, ok := objectToASTNode(e)
+			if !ok {
+				return nil, false
+			}
+			elements = append(elements, element)
+		}
+
+		return &ast.ArrayLiteral{
+			Token:    token.Token{Type: token.LBRACKET, Literal: "["},
+			Elements: elements,
+		}, true
+	case *object.Quote:
+		exp, ok := obj.Node.(ast.Expression)
+		return exp, ok
+	}
+
+	return nil, false
+}
+
+// objectType return type of obj, nil is result of empty block
+func objectType(obj object.Object) object.Type {
+	if obj == nil {
+		return object.NULL_OBJ
+	}
+
+	return obj.Type()
+}
@@ -0,0 +1,56 @@
+package evaluator
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+type randKey struct{}
+
+// defaultRand is used when host does not set generator, seeded by current time
+var defaultRand = rand.New(&lockedSource{
+	src: rand.NewSource(time.Now().UnixNano()),
+})
+
+// SetRand set generator of random builtins on env,
+// it is locked since spawned tasks share it
+func SetRand(env *object.Environment, r *rand.Rand) {
+	env.SetValue(randKey{}, rand.New(&lockedSource{src: r}))
+}
+
+// SetSeed set generator seeded by seed on env, same seed gives same sequence
+func SetSeed(env *object.Environment, seed int64) {
+	env.SetValue(randKey{}, rand.New(&lockedSource{
+		src: rand.NewSource(seed),
+	}))
+}
+
+// random return generator set by SetRand, defaultRand if not set
+func random(env *object.Environment) *rand.Rand {
+	if r, ok := env.Value(randKey{}).(*rand.Rand); ok {
+		return r
+	}
+
+	return defaultRand
+}
+
+// lockedSource is rand.Source safe for concurrent use like source of math/rand
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
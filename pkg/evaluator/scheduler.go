@@ -0,0 +1,175 @@
+package evaluator
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+type schedulerKey struct{}
+
+// deadlockInterval is interval to check whether all tasks are blocked
+var deadlockInterval = 10 * time.Millisecond
+
+// deadlockTicks is number of checks without progress to report deadlock,
+// it gives woken task time to record progress
+const deadlockTicks = 5
+
+// scheduler track tasks of a script, including the one evaluating program,
+// to detect deadlock where all of them are blocked on channels or tasks
+type scheduler struct {
+	mu       sync.Mutex
+	running  int
+	blocked  int
+	progress uint64
+	// stalled is progress when all tasks were found blocked
+	stalled      uint64
+	stalledTicks int
+	// deadlock is closed when deadlock is detected, then replaced
+	deadlock chan struct{}
+}
+
+// startScheduler set new scheduler on env of host entry point, so that
+// evaluations sharing globals, such as children of frozen Interpreter, do
+// not see deadlock of each other. frozen env is enclosed by new Env to hold
+// it. returned func must be called when evaluation finishes.
+func startScheduler(env *object.Environment) (*object.Environment, func()) {
+	s := &scheduler{
+		running:  1,
+		deadlock: make(chan struct{}),
+	}
+
+	if !env.IsFrozen() {
+		env.SetValue(schedulerKey{}, s)
+		return env, s.finished
+	}
+
+	env = object.NewEnclosedEnvironment(env)
+	env.SetValue(schedulerKey{}, s)
+	env.Freeze()
+
+	return env, s.finished
+}
+
+func currentScheduler(env *object.Environment, name string) (*scheduler, *object.Error) {
+	if s, ok := env.Value(schedulerKey{}).(*scheduler); ok {
+		return s, nil
+	}
+
+	return nil, newError("%s: not available outside of evaluation", name)
+}
+
+func (s *scheduler) spawned() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running++
+}
+
+func (s *scheduler) finished() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running--
+	s.progress++
+}
+
+// block record caller is blocked, and return channel closed on deadlock
+func (s *scheduler) block() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blocked++
+	return s.deadlock
+}
+
+func (s *scheduler) unblock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blocked--
+	s.progress++
+}
+
+// check close deadlock channel if all tasks stay blocked without progress
+func (s *scheduler) check() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.blocked < s.running || s.stalled != s.progress {
+		s.stalled = s.progress
+		s.stalledTicks = 0
+		return
+	}
+
+	s.stalledTicks++
+	if s.stalledTicks < deadlockTicks {
+		return
+	}
+
+	close(s.deadlock)
+	s.deadlock = make(chan struct{})
+	s.stalledTicks = 0
+}
+
+// wait select cases like reflect.Select until one of them proceeds.
+// it fails on deadlock, when context of env is done, or on panic such as
+// send on closed channel.
+func wait(env *object.Environment, name string, cases []reflect.SelectCase) (chosen int, recv reflect.Value, recvOK bool, err *object.Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newError("%s: %v", name, r)
+		}
+	}()
+
+	s, err := currentScheduler(env, name)
+	if err != nil {
+		return 0, recv, false, err
+	}
+
+	// most of operations proceed without blocking
+	nonblocking := append(cases[:len(cases):len(cases)], reflect.SelectCase{Dir: reflect.SelectDefault})
+	if chosen, recv, recvOK = reflect.Select(nonblocking); chosen < len(cases) {
+		return chosen, recv, recvOK, nil
+	}
+
+	deadlock := s.block()
+	defer s.unblock()
+
+	ticker := time.NewTicker(deadlockInterval)
+	defer ticker.Stop()
+
+	all := append(cases[:len(cases):len(cases)],
+		reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(deadlock)},
+		reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ticker.C)},
+	)
+
+	var ctx context.Context
+	if c, ok := env.Value(contextKey{}).(context.Context); ok {
+		ctx = c
+		all = append(all, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	}
+
+	for {
+		chosen, recv, recvOK = reflect.Select(all)
+
+		switch chosen - len(cases) {
+		case 0:
+			// other task may be woken by the deadlock before us
+			if chosen, recv, recvOK = reflect.Select(nonblocking); chosen < len(cases) {
+				return chosen, recv, recvOK, nil
+			}
+
+			return chosen, recv, recvOK, newError("%s: deadlock, all tasks are blocked", name)
+		case 1:
+			s.check()
+		case 2:
+			return chosen, recv, recvOK, newKindError(object.LIMIT_ERROR, "evaluation stopped: %s", ctx.Err())
+		default:
+			return chosen, recv, recvOK, nil
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package evaluator
+
+import (
+	"github.com/naoto0822/monkey-interpreter/pkg/ast"
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func evalStructStatement(node *ast.StructStatement, env *object.Environment) object.Object {
+	if env.IsFrozen() {
+		return newError("cannot bind %s in frozen environment", node.Name.Value)
+	}
+
+	fields := make([]string, 0, len(node.Fields))
+	for _, f := range node.Fields {
+		fields = append(fields, f.Value)
+	}
+
+	env.Set(node.Name.Value, &object.StructType{
+		Name:   node.Name.Value,
+		Fields: fields,
+	})
+
+	return nil
+}
+
+func evalStructIndexExpression(left, index object.Object) object.Object {
+	s := left.(*object.Struct)
+
+	key, ok := index.(*object.String)
+	if !ok {
+		return newTypeError("struct index must be STRING, got %s", index.Type())
+	}
+
+	if value, ok := s.Get(key.Value); ok {
+		return value
+	}
+
+	return newNameError("%s has no field %s", s.StructType.Name, key.Value)
+}
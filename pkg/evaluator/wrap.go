@@ -0,0 +1,125 @@
+package evaluator
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Wrap convert Go func fn to Builtin.
+// parameters and results are converted by object.ToGo and object.FromGo.
+// last result can be error, which is returned to script as RuntimeError.
+func Wrap(fn interface{}) (*object.Builtin, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil, fmt.Errorf("wrap: %T is not func", fn)
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumIn(); i++ {
+		if !object.Convertible(t.In(i)) {
+			return nil, fmt.Errorf("wrap: unsupported parameter type %s", t.In(i))
+		}
+	}
+
+	results := t.NumOut()
+	hasError := results > 0 && t.Out(results-1) == errorType
+	if hasError {
+		results--
+	}
+
+	if results > 1 {
+		return nil, fmt.Errorf("wrap: too many results of %s", t)
+	}
+
+	if results == 1 && !object.Convertible(t.Out(0)) {
+		return nil, fmt.Errorf("wrap: unsupported result type %s", t.Out(0))
+	}
+
+	return &object.Builtin{
+		Fn: func(env *object.Environment, args ...object.Object) (result object.Object) {
+			in, err := wrapArguments(t, args)
+			if err != nil {
+				return err
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					result = newError("panic: %v", r)
+				}
+			}()
+
+			out := v.Call(in)
+
+			if hasError && !out[len(out)-1].IsNil() {
+				callErr := out[len(out)-1].Interface().(error)
+				if objErr, ok := callErr.(*object.Error); ok {
+					return objErr
+				}
+
+				return newError("%s", callErr)
+			}
+
+			if results == 0 {
+				return NULL
+			}
+
+			obj, convErr := object.FromGo(out[0].Interface())
+			if convErr != nil {
+				return newTypeError("%s", convErr)
+			}
+
+			return obj
+		},
+	}, nil
+}
+
+// wrapArguments convert args to arguments of func type t
+func wrapArguments(t reflect.Type, args []object.Object) ([]reflect.Value, *object.Error) {
+	params := t.NumIn()
+	if t.IsVariadic() {
+		if len(args) < params-1 {
+			return nil, newArgumentError("wrong number of arguments. got=%d, want>=%d",
+				len(args), params-1)
+		}
+	} else if len(args) != params {
+		return nil, newArgumentError("wrong number of arguments. got=%d, want=%d",
+			len(args), params)
+	}
+
+	in := make([]reflect.Value, len(args), len(args))
+	for i, arg := range args {
+		var pt reflect.Type
+		if t.IsVariadic() && i >= params-1 {
+			pt = t.In(params - 1).Elem()
+		} else {
+			pt = t.In(i)
+		}
+
+		value := reflect.New(pt)
+		if err := object.ToGo(arg, value.Interface()); err != nil {
+			return nil, newTypeError("argument %d%s", i+1, describeConvertError(err))
+		}
+
+		in[i] = value.Elem()
+	}
+
+	return in, nil
+}
+
+// describeConvertError format err as following "argument N"
+func describeConvertError(err error) string {
+	ce, ok := err.(*object.ConvertError)
+	if !ok {
+		return " " + err.Error()
+	}
+
+	if ce.Path == "" {
+		return " " + ce.Message
+	}
+
+	return " at " + ce.Path + " " + ce.Message
+}
@@ -33,6 +33,7 @@ if (5 < 10) {
 [1, 2];
 
 {"foo": "bar"}
+a.b
 	`
 
 	tests := []struct {
@@ -125,6 +126,9 @@ if (5 < 10) {
 		{token.COLON, ":"},
 		{token.STRING, "bar"},
 		{token.RBRACE, "}"},
+		{token.IDENT, "a"},
+		{token.DOT, "."},
+		{token.IDENT, "b"},
 		{token.EOF, ""},
 	}
 
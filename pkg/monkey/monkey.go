@@ -0,0 +1,260 @@
+// Package monkey is entry point to embed Monkey interpreter in Go program.
+package monkey
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/ast"
+	"github.com/naoto0822/monkey-interpreter/pkg/evaluator"
+	"github.com/naoto0822/monkey-interpreter/pkg/lexer"
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+	"github.com/naoto0822/monkey-interpreter/pkg/optimizer"
+	"github.com/naoto0822/monkey-interpreter/pkg/parser"
+)
+
+// Interpreter keep globals between Run and Call.
+// Run, RunFile and Call of one Interpreter must not be called concurrently,
+// since deadline of each of them is set on it. use Child of frozen
+// Interpreter for each concurrent evaluation instead.
+type Interpreter struct {
+	// host has host values set by options, outer of env
+	host *object.Environment
+	// env has globals
+	env      *object.Environment
+	timeout  time.Duration
+	optimize bool
+}
+
+// Option configure Interpreter
+type Option func(*Interpreter)
+
+// WithOutput set writer of puts and print
+func WithOutput(w io.Writer) Option {
+	return func(i *Interpreter) {
+		evaluator.SetOutput(i.host, w)
+	}
+}
+
+// WithInput set reader of read_line
+func WithInput(r io.Reader) Option {
+	return func(i *Interpreter) {
+		evaluator.SetInput(i.host, r)
+	}
+}
+
+// WithRand set generator of random builtins
+func WithRand(r *rand.Rand) Option {
+	return func(i *Interpreter) {
+		evaluator.SetRand(i.host, r)
+	}
+}
+
+// WithSeed set generator seeded by seed, same seed gives same sequence
+func WithSeed(seed int64) Option {
+	return func(i *Interpreter) {
+		evaluator.SetSeed(i.host, seed)
+	}
+}
+
+// WithClock set clock of now
+func WithClock(clock func() time.Time) Option {
+	return func(i *Interpreter) {
+		evaluator.SetClock(i.host, clock)
+	}
+}
+
+// WithFileSystem enable file builtins under fs
+func WithFileSystem(fs *evaluator.FileSystem) Option {
+	return func(i *Interpreter) {
+		evaluator.SetFileSystem(i.host, fs)
+	}
+}
+
+// WithImportPath set directories to search modules of import
+func WithImportPath(dirs ...string) Option {
+	return func(i *Interpreter) {
+		evaluator.SetImportPath(i.host, dirs...)
+	}
+}
+
+// WithMaxCallDepth limit depth of nested function calls
+func WithMaxCallDepth(depth int) Option {
+	return func(i *Interpreter) {
+		evaluator.SetMaxCallDepth(i.host, depth)
+	}
+}
+
+// WithTimeout limit time of each Run, RunFile and Call
+func WithTimeout(timeout time.Duration) Option {
+	return func(i *Interpreter) {
+		i.timeout = timeout
+	}
+}
+
+// WithOptimize fold constant expressions of program before evaluation,
+// let constants are not inlined since globals persist across Run and Set
+func WithOptimize() Option {
+	return func(i *Interpreter) {
+		i.optimize = true
+	}
+}
+
+// New return Interpreter configured by opts
+func New(opts ...Option) *Interpreter {
+	host := object.NewEnvironment()
+	i := &Interpreter{
+		host: host,
+		env:  object.NewEnclosedEnvironment(host),
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Freeze make globals of i read-only, so that Child of i can be used
+// concurrently without lock on shared globals. Run on frozen i can not
+// bind globals anymore.
+func (i *Interpreter) Freeze() {
+	i.env.Freeze()
+}
+
+// Child return Interpreter seeing globals of i, such as per request.
+// bindings and options of Child do not affect i.
+func (i *Interpreter) Child(opts ...Option) *Interpreter {
+	host := object.NewEnclosedEnvironment(i.env)
+	child := &Interpreter{
+		host:     host,
+		env:      object.NewEnclosedEnvironment(host),
+		timeout:  i.timeout,
+		optimize: i.optimize,
+	}
+
+	for _, opt := range opts {
+		opt(child)
+	}
+
+	return child
+}
+
+// ParseError is returned when source has syntax errors
+type ParseError struct {
+	Errors []string
+}
+
+// Error implements error
+func (e *ParseError) Error() string {
+	return "parse error: " + strings.Join(e.Errors, "; ")
+}
+
+// Run evaluate src and return value of last statement.
+// error of script is returned as *object.Error.
+func (i *Interpreter) Run(src string) (object.Object, error) {
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) > 0 {
+		return nil, &ParseError{Errors: p.Errors()}
+	}
+
+	defer i.start()()
+
+	if err := evaluator.DefineMacros(program, i.env); err != nil {
+		return nil, err
+	}
+
+	expanded, err := evaluator.ExpandMacros(program, i.env)
+	if err != nil {
+		return nil, err
+	}
+	program = expanded.(*ast.Program)
+
+	if i.optimize {
+		program = optimizer.Fold(program)
+	}
+
+	return result(evaluator.Eval(program, i.env))
+}
+
+// RunFile evaluate file at path
+func (i *Interpreter) RunFile(path string) (object.Object, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.Run(string(src))
+}
+
+// Set bind value to global name, it panics after Freeze
+func (i *Interpreter) Set(name string, value object.Object) {
+	i.env.Set(name, value)
+}
+
+// Get return value of global name
+func (i *Interpreter) Get(name string) (object.Object, bool) {
+	return i.env.Get(name)
+}
+
+// Register expose fn to scripts of i as global name, it panics after Freeze.
+// fn is *object.Builtin or Go func converted by evaluator.Wrap.
+func (i *Interpreter) Register(name string, fn interface{}) error {
+	b, ok := fn.(*object.Builtin)
+	if !ok {
+		var err error
+		if b, err = evaluator.Wrap(fn); err != nil {
+			return err
+		}
+	}
+
+	evaluator.Register(i.env, name, b)
+	return nil
+}
+
+// Call call global function or builtin name with args
+func (i *Interpreter) Call(name string, args ...object.Object) (object.Object, error) {
+	fn, ok := evaluator.Lookup(i.env, name)
+	if !ok {
+		return nil, fmt.Errorf("monkey: function not found: %s", name)
+	}
+
+	defer i.start()()
+	return result(evaluator.Apply(fn, args, i.env))
+}
+
+// start set deadline of evaluation, returned func must be called after it
+// to remove deadline
+func (i *Interpreter) start() func() {
+	if i.timeout <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), i.timeout)
+	evaluator.SetContext(i.host, ctx)
+
+	return func() {
+		cancel()
+		// done context must not stop later evaluation, such as of Child
+		evaluator.SetContext(i.host, nil)
+	}
+}
+
+func result(obj object.Object) (object.Object, error) {
+	if err, ok := obj.(*object.Error); ok {
+		return nil, err
+	}
+
+	if obj == nil {
+		return object.NULL, nil
+	}
+
+	return obj, nil
+}
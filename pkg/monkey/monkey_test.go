@@ -0,0 +1,425 @@
+package monkey
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+)
+
+func TestRun(t *testing.T) {
+	i := New()
+
+	obj, err := i.Run(`let add = fn(a, b) { a + b }; add(1, 2)`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	testInteger(t, obj, 3)
+
+	// globals are kept between runs
+	obj, err = i.Run(`add(10, 20)`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	testInteger(t, obj, 30)
+}
+
+func TestRunErrors(t *testing.T) {
+	i := New()
+
+	_, err := i.Run(`let = 1`)
+	perr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("err is not *ParseError. got=%T (%v)", err, err)
+	}
+	if len(perr.Errors) == 0 {
+		t.Errorf("ParseError has no errors")
+	}
+
+	_, err = i.Run(`1 / 0`)
+	oerr, ok := err.(*object.Error)
+	if !ok {
+		t.Fatalf("err is not *object.Error. got=%T (%v)", err, err)
+	}
+	if err.Error() != "RuntimeError: division by zero" {
+		t.Errorf("err is not division by zero. got=%q", err.Error())
+	}
+	if oerr.Kind != object.RUNTIME_ERROR {
+		t.Errorf("err.Kind is not %s. got=%s", object.RUNTIME_ERROR, oerr.Kind)
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "main.mk")
+	if err := ioutil.WriteFile(path, []byte(`puts("hello"); 42`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	obj, err := New(WithOutput(&out)).RunFile(path)
+	if err != nil {
+		t.Fatalf("RunFile returned error: %s", err)
+	}
+
+	testInteger(t, obj, 42)
+	if out.String() != "hello\n" {
+		t.Errorf("output is not %q. got=%q", "hello\n", out.String())
+	}
+
+	if _, err := New().RunFile(filepath.Join(dir, "none.mk")); err == nil {
+		t.Errorf("RunFile does not return error for missing file")
+	}
+}
+
+func TestImportPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `puts("loaded"); export let square = fn(x) { x * x };`
+	if err := ioutil.WriteFile(filepath.Join(dir, "lib.mk"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	base := New(WithOutput(&out), WithImportPath(dir))
+	if _, err := base.Run(`import "lib";`); err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	base.Freeze()
+
+	// children share modules loaded by base
+	obj, err := base.Child().Run(`import "lib" as l; l["square"](lib["square"](2))`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	testInteger(t, obj, 16)
+	if out.String() != "loaded\n" {
+		t.Errorf("output is not %q. got=%q", "loaded\n", out.String())
+	}
+}
+
+func TestMacros(t *testing.T) {
+	var out bytes.Buffer
+	i := New(WithOutput(&out))
+
+	if _, err := i.Run(`
+let unless = macro(condition, consequence, alternative) {
+	quote(if (!(unquote(condition))) { unquote(consequence); } else { unquote(alternative); });
+};
+`); err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	// macros are kept between Run
+	obj, err := i.Run(`unless(10 > 5, puts("not greater"), puts("greater")); unless(false, 1, 2)`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	testInteger(t, obj, 1)
+	if out.String() != "greater\n" {
+		t.Errorf("output is not %q. got=%q", "greater\n", out.String())
+	}
+
+	if _, err := i.Run(`let m = macro() { 1 }; m()`); err == nil {
+		t.Errorf("Run does not return error of macro expansion")
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	i := New()
+	i.Set("limit", &object.Integer{Value: 5})
+
+	if _, err := i.Run(`let doubled = limit * 2;`); err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	obj, ok := i.Get("doubled")
+	if !ok {
+		t.Fatalf("doubled is not found")
+	}
+	testInteger(t, obj, 10)
+
+	if _, ok := i.Get("none"); ok {
+		t.Errorf("none is found")
+	}
+}
+
+func TestOptimizeSet(t *testing.T) {
+	i := New(WithOptimize())
+	if _, err := i.Run(`let rate = 10; let price = fn(n) { n * rate };`); err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	// globals are not inlined since they can be rebound
+	i.Set("rate", &object.Integer{Value: 20})
+	obj, err := i.Run(`price(2)`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	testInteger(t, obj, 40)
+
+	obj, err = i.Run(`let rate = 30; price(1 + 1)`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	testInteger(t, obj, 60)
+}
+
+func TestCall(t *testing.T) {
+	i := New()
+	if _, err := i.Run(`let mul = fn(a, b) { a * b }; let fail = fn() { throw "oops" };`); err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	obj, err := i.Call("mul", &object.Integer{Value: 6}, &object.Integer{Value: 7})
+	if err != nil {
+		t.Fatalf("Call returned error: %s", err)
+	}
+	testInteger(t, obj, 42)
+
+	obj, err = i.Call("len", &object.String{Value: "abc"})
+	if err != nil {
+		t.Fatalf("Call returned error: %s", err)
+	}
+	testInteger(t, obj, 3)
+
+	if _, err := i.Call("fail"); err == nil || err.Error() != "Error: oops" {
+		t.Errorf("Call does not return thrown error. got=%v", err)
+	}
+
+	if _, err := i.Call("none"); err == nil {
+		t.Errorf("Call does not return error for unknown function")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	prices := map[string]int64{"apple": 120}
+
+	i := New()
+	err := i.Register("price", func(name string) (int64, error) {
+		price, ok := prices[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown item: %s", name)
+		}
+		return price, nil
+	})
+	if err != nil {
+		t.Fatalf("Register returned error: %s", err)
+	}
+
+	obj, err := i.Run(`price("apple") * 2`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	testInteger(t, obj, 240)
+
+	obj, err = i.Run(`try { price("pear") } catch (e) { -1 }`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	testInteger(t, obj, -1)
+
+	if _, err := New().Run(`price("apple")`); err == nil {
+		t.Errorf("registered function leaks into other interpreter")
+	}
+
+	if err := i.Register("bad", 1); err == nil {
+		t.Errorf("Register does not return error for non-func")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	src := `let loop = fn(n) { loop(n + 1) }; try { loop(0) } catch (e) { 0 }`
+
+	_, err := New(WithMaxCallDepth(50)).Run(src)
+	if err == nil || err.Error() != "LimitError: maximum call depth 50 exceeded" {
+		t.Errorf("max call depth is not applied. got=%v", err)
+	}
+
+	_, err = New(WithTimeout(10 * time.Millisecond)).Run(src)
+	if err == nil || err.Error() != "LimitError: evaluation stopped: context deadline exceeded" {
+		t.Errorf("timeout is not applied. got=%v", err)
+	}
+
+	obj, err := New(WithMaxCallDepth(50)).Run(`let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } }; f(49)`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	testInteger(t, obj, 0)
+
+	// deadline of finished Run is not seen by Child w/o timeout
+	base := New(WithTimeout(time.Second))
+	if _, err := base.Run(`let f = fn() { 1 };`); err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	obj, err = base.Child(WithTimeout(0)).Run(`f()`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	testInteger(t, obj, 1)
+}
+
+func TestConcurrentChildren(t *testing.T) {
+	base := New()
+	if _, err := base.Run(`
+let greet = fn(name) { puts("hello, " + name); name };
+let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } };
+`); err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	base.Freeze()
+
+	if _, err := base.Run(`let x = 1;`); err == nil {
+		t.Errorf("frozen Interpreter binds global")
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+
+	for n := 0; n < 50; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+
+			var out bytes.Buffer
+			child := base.Child(WithOutput(&out), WithMaxCallDepth(100))
+			child.Set("n", &object.Integer{Value: int64(n)})
+
+			obj, err := child.Run(`let name = "user" + str(n); greet(name); fib(10)`)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if obj.Inspect() != "55" {
+				errs <- fmt.Errorf("fib(10) is not 55. got=%s", obj.Inspect())
+			}
+
+			// output of library function goes to writer of the child
+			expected := fmt.Sprintf("hello, user%d\n", n)
+			if out.String() != expected {
+				errs <- fmt.Errorf("output is not %q. got=%q", expected, out.String())
+			}
+		}(n)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if _, ok := base.Get("name"); ok {
+		t.Errorf("binding of child leaks into base")
+	}
+}
+
+func TestConcurrentChildrenDeadlock(t *testing.T) {
+	base := New()
+	if _, err := base.Run(`
+let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } };
+let relay = fn(c) { fib(22); send(c, 6) };
+`); err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+	base.Freeze()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	for n := 0; n < 2; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+
+			child := base.Child()
+
+			// deadlock of a child must not fail others
+			if n == 0 {
+				_, err := child.Run(`recv(channel())`)
+				if err == nil || !strings.Contains(err.Error(), "deadlock") {
+					errs <- fmt.Errorf("deadlock is not detected. got=%v", err)
+				}
+				return
+			}
+
+			obj, err := child.Run(`let c = channel(); spawn(relay, c); recv(c) + 1`)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if obj.Inspect() != "7" {
+				errs <- fmt.Errorf("result is not 7. got=%s", obj.Inspect())
+			}
+		}(n)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestSpawnSharedHostValues should be run w/ -race
+func TestSpawnSharedHostValues(t *testing.T) {
+	var out bytes.Buffer
+	interp := New(
+		WithOutput(&out),
+		WithRand(rand.New(rand.NewSource(1))),
+	)
+
+	obj, err := interp.Run(`
+let draw = fn(n) { let r = random(); puts(n); r };
+let tasks = map(range(0, 8), fn(n) { spawn(draw, n) });
+len(map(tasks, fn(t) { await(t) }))
+`)
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err)
+	}
+
+	testInteger(t, obj, 8)
+
+	if lines := strings.Count(out.String(), "\n"); lines != 8 {
+		t.Errorf("wrong number of lines. want=8, got=%d (%q)", lines, out.String())
+	}
+}
+
+func testInteger(t *testing.T, obj object.Object, expected int64) {
+	t.Helper()
+
+	integer, ok := obj.(*object.Integer)
+	if !ok {
+		t.Errorf("obj is not object.Integer. got=%T (%+v)", obj, obj)
+		return
+	}
+
+	if integer.Value != expected {
+		t.Errorf("integer.Value is not %d. got=%d", expected, integer.Value)
+	}
+}
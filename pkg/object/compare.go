@@ -0,0 +1,249 @@
+package object
+
+// Equaler is object compared by its value
+type Equaler interface {
+	Equal(other Object) bool
+}
+
+// Comparer is object which has order.
+// Compare return -1, 0 or +1, ok is false if other can not be compared.
+type Comparer interface {
+	Compare(other Object) (result int, ok bool)
+}
+
+// Equal report whether a and b are structurally equal,
+// objects without Equaler are equal only if they are identical.
+func Equal(a, b Object) bool {
+	if a == b {
+		return true
+	}
+
+	if a == nil || b == nil || a.Type() != b.Type() {
+		return false
+	}
+
+	if e, ok := a.(Equaler); ok {
+		return e.Equal(b)
+	}
+
+	return false
+}
+
+// Compare a with b, ok is false if they have no order
+func Compare(a, b Object) (int, bool) {
+	if a == nil || b == nil || a.Type() != b.Type() {
+		return 0, false
+	}
+
+	if c, ok := a.(Comparer); ok {
+		return c.Compare(b)
+	}
+
+	return 0, false
+}
+
+var _ Equaler = (*Integer)(nil)
+var _ Comparer = (*Integer)(nil)
+
+// Equal implements Equaler
+func (i *Integer) Equal(other Object) bool {
+	o, ok := other.(*Integer)
+	return ok && i.Value == o.Value
+}
+
+// Compare implements Comparer
+func (i *Integer) Compare(other Object) (int, bool) {
+	o, ok := other.(*Integer)
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case i.Value < o.Value:
+		return -1, true
+	case i.Value > o.Value:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+var _ Equaler = (*Boolean)(nil)
+var _ Comparer = (*Boolean)(nil)
+
+// Equal implements Equaler
+func (b *Boolean) Equal(other Object) bool {
+	o, ok := other.(*Boolean)
+	return ok && b.Value == o.Value
+}
+
+// Compare implements Comparer, false is less than true
+func (b *Boolean) Compare(other Object) (int, bool) {
+	o, ok := other.(*Boolean)
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case b.Value == o.Value:
+		return 0, true
+	case o.Value:
+		return -1, true
+	default:
+		return 1, true
+	}
+}
+
+var _ Equaler = (*Null)(nil)
+
+// Equal implements Equaler
+func (n *Null) Equal(other Object) bool {
+	_, ok := other.(*Null)
+	return ok
+}
+
+var _ Equaler = (*String)(nil)
+var _ Comparer = (*String)(nil)
+
+// Equal implements Equaler
+func (s *String) Equal(other Object) bool {
+	o, ok := other.(*String)
+	return ok && s.Value == o.Value
+}
+
+// Compare implements Comparer
+func (s *String) Compare(other Object) (int, bool) {
+	o, ok := other.(*String)
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case s.Value < o.Value:
+		return -1, true
+	case s.Value > o.Value:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+var _ Equaler = (*Array)(nil)
+var _ Comparer = (*Array)(nil)
+
+// Equal implements Equaler
+func (a *Array) Equal(other Object) bool {
+	o, ok := other.(*Array)
+	if !ok || len(a.Elements) != len(o.Elements) {
+		return false
+	}
+
+	for i, e := range a.Elements {
+		if !Equal(e, o.Elements[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compare implements Comparer, elements are compared in order
+func (a *Array) Compare(other Object) (int, bool) {
+	o, ok := other.(*Array)
+	if !ok {
+		return 0, false
+	}
+
+	for i := 0; i < len(a.Elements) && i < len(o.Elements); i++ {
+		result, ok := Compare(a.Elements[i], o.Elements[i])
+		if !ok {
+			return 0, false
+		}
+
+		if result != 0 {
+			return result, true
+		}
+	}
+
+	switch {
+	case len(a.Elements) < len(o.Elements):
+		return -1, true
+	case len(a.Elements) > len(o.Elements):
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+var _ Equaler = (*Hash)(nil)
+
+// Equal implements Equaler
+func (h *Hash) Equal(other Object) bool {
+	o, ok := other.(*Hash)
+	if !ok || h.Len() != o.Len() {
+		return false
+	}
+
+	for _, bucket := range h.Pairs {
+		for _, pair := range bucket {
+			value, ok := o.Get(pair.Key)
+			if !ok || !Equal(pair.Value, value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+var _ Equaler = (*Regex)(nil)
+
+// Equal implements Equaler, regexes are equal if patterns are same
+func (r *Regex) Equal(other Object) bool {
+	o, ok := other.(*Regex)
+	return ok && r.Value.String() == o.Value.String()
+}
+
+var _ Equaler = (*Time)(nil)
+var _ Comparer = (*Time)(nil)
+
+// Equal implements Equaler, times are equal if they are same instant
+func (t *Time) Equal(other Object) bool {
+	o, ok := other.(*Time)
+	return ok && t.Value.Equal(o.Value)
+}
+
+// Compare implements Comparer
+func (t *Time) Compare(other Object) (int, bool) {
+	o, ok := other.(*Time)
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case t.Value.Before(o.Value):
+		return -1, true
+	case t.Value.After(o.Value):
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+var _ Equaler = (*Struct)(nil)
+
+// Equal implements Equaler, instances of different StructType are not equal
+func (s *Struct) Equal(other Object) bool {
+	o, ok := other.(*Struct)
+	if !ok || s.StructType != o.StructType {
+		return false
+	}
+
+	for i, v := range s.Values {
+		if !Equal(v, o.Values[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,127 @@
+package object
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a        Object
+		b        Object
+		expected bool
+	}{
+		{&Integer{Value: 1}, &Integer{Value: 1}, true},
+		{&Integer{Value: 1}, &Integer{Value: 2}, false},
+		{&Integer{Value: 1}, &String{Value: "1"}, false},
+		{&String{Value: "a"}, &String{Value: "a"}, true},
+		{&Boolean{Value: true}, &Boolean{Value: true}, true},
+		{&Null{}, &Null{}, true},
+		{
+			&Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "a"}}},
+			&Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "a"}}},
+			true,
+		},
+		{
+			&Array{Elements: []Object{&Integer{Value: 1}}},
+			&Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}},
+			false,
+		},
+		{
+			newTestHash("a", &Integer{Value: 1}),
+			newTestHash("a", &Integer{Value: 1}),
+			true,
+		},
+		{
+			newTestHash("a", &Integer{Value: 1}),
+			newTestHash("a", &Integer{Value: 2}),
+			false,
+		},
+		{&Builtin{}, &Builtin{}, false},
+		{
+			&Regex{Value: regexp.MustCompile("a+")},
+			&Regex{Value: regexp.MustCompile("a+")},
+			true,
+		},
+		{
+			&Time{Value: time.Date(2020, 1, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))},
+			&Time{Value: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			true,
+		},
+		{
+			&Struct{StructType: testPoint, Values: []Object{&Integer{Value: 1}, &Integer{Value: 2}}},
+			&Struct{StructType: testPoint, Values: []Object{&Integer{Value: 1}, &Integer{Value: 2}}},
+			true,
+		},
+		{
+			&Struct{StructType: testPoint, Values: []Object{&Integer{Value: 1}, &Integer{Value: 2}}},
+			&Struct{StructType: &StructType{Name: "Point", Fields: []string{"x", "y"}},
+				Values: []Object{&Integer{Value: 1}, &Integer{Value: 2}}},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		if Equal(tt.a, tt.b) != tt.expected {
+			t.Errorf("Equal(%s, %s) is not %t", tt.a.Inspect(), tt.b.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a          Object
+		b          Object
+		expected   int
+		expectedOK bool
+	}{
+		{&Integer{Value: 1}, &Integer{Value: 2}, -1, true},
+		{&Integer{Value: 2}, &Integer{Value: 2}, 0, true},
+		{&Integer{Value: 3}, &Integer{Value: 2}, 1, true},
+		{&String{Value: "a"}, &String{Value: "b"}, -1, true},
+		{&Boolean{Value: false}, &Boolean{Value: true}, -1, true},
+		{
+			&Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}},
+			&Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 3}}},
+			-1,
+			true,
+		},
+		{
+			&Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}},
+			&Array{Elements: []Object{&Integer{Value: 1}}},
+			1,
+			true,
+		},
+		{&Integer{Value: 1}, &String{Value: "1"}, 0, false},
+		{&Null{}, &Null{}, 0, false},
+		{
+			&Time{Value: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			&Time{Value: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+			-1,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, ok := Compare(tt.a, tt.b)
+		if ok != tt.expectedOK {
+			t.Errorf("Compare(%s, %s) ok is not %t", tt.a.Inspect(), tt.b.Inspect(), tt.expectedOK)
+			continue
+		}
+
+		if result != tt.expected {
+			t.Errorf("Compare(%s, %s) is not %d. got=%d",
+				tt.a.Inspect(), tt.b.Inspect(), tt.expected, result)
+		}
+	}
+}
+
+var testPoint = &StructType{Name: "Point", Fields: []string{"x", "y"}}
+
+func newTestHash(key string, value Object) *Hash {
+	hash := NewHash()
+	hash.Set(&String{Value: key}, value)
+
+	return hash
+}
@@ -0,0 +1,489 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	objectType = reflect.TypeOf((*Object)(nil)).Elem()
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// ConvertError is returned by FromGo and ToGo
+type ConvertError struct {
+	// Path is location of failed value such as [0]["name"], empty for top level
+	Path    string
+	Message string
+}
+
+// Error implements error
+func (e *ConvertError) Error() string {
+	if e.Path == "" {
+		return "object: " + e.Message
+	}
+
+	return "object: " + e.Path + " " + e.Message
+}
+
+func newConvertError(format string, a ...interface{}) *ConvertError {
+	return &ConvertError{Message: fmt.Sprintf(format, a...)}
+}
+
+// at prepend location to Path of err
+func at(err error, location string) error {
+	if ce, ok := err.(*ConvertError); ok {
+		ce.Path = location + ce.Path
+	}
+
+	return err
+}
+
+// FromGo convert Go value to Object.
+// struct is converted to Hash keyed by field name or `monkey:"name"` tag,
+// fields tagged `monkey:"-"` and unexported fields are skipped.
+// nil and nil pointer, slice and map are NULL, cyclic value is error.
+func FromGo(v interface{}) (Object, error) {
+	return fromValue(reflect.ValueOf(v), make(map[visit]bool))
+}
+
+// visit is pointer, map or slice being converted, it is kept in visiting
+// while its elements are converted to find cycle
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter add v to visiting, it is false if v is already being converted
+func enter(v reflect.Value, visiting map[visit]bool) (visit, bool) {
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+
+	if visiting[key] {
+		return key, false
+	}
+	visiting[key] = true
+
+	return key, true
+}
+
+func fromValue(v reflect.Value, visiting map[visit]bool) (Object, error) {
+	if !v.IsValid() {
+		return NULL, nil
+	}
+
+	if obj, ok := asObject(v); ok {
+		return obj, nil
+	}
+
+	if v.Type() == timeType {
+		return &Time{Value: v.Interface().(time.Time)}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Integer{Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if int64(u) < 0 {
+			return nil, newConvertError("%d overflows INTEGER", u)
+		}
+		return &Integer{Value: int64(u)}, nil
+	case reflect.String:
+		return &String{Value: v.String()}, nil
+	case reflect.Bool:
+		if v.Bool() {
+			return TRUE, nil
+		}
+		return FALSE, nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return NULL, nil
+		}
+
+		if v.Kind() == reflect.Ptr {
+			key, ok := enter(v, visiting)
+			if !ok {
+				return nil, newConvertError("cyclic value of type %s", v.Type())
+			}
+			defer delete(visiting, key)
+		}
+		return fromValue(v.Elem(), visiting)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return NULL, nil
+		}
+
+		if v.Kind() == reflect.Slice && v.Len() > 0 {
+			key, ok := enter(v, visiting)
+			if !ok {
+				return nil, newConvertError("cyclic value of type %s", v.Type())
+			}
+			defer delete(visiting, key)
+		}
+
+		elements := make([]Object, v.Len(), v.Len())
+		for i := range elements {
+			e, err := fromValue(v.Index(i), visiting)
+			if err != nil {
+				return nil, at(err, fmt.Sprintf("[%d]", i))
+			}
+			elements[i] = e
+		}
+		return &Array{Elements: elements}, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return NULL, nil
+		}
+
+		key, ok := enter(v, visiting)
+		if !ok {
+			return nil, newConvertError("cyclic value of type %s", v.Type())
+		}
+		defer delete(visiting, key)
+
+		hash := NewHash()
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := fromValue(iter.Key(), visiting)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := fromValue(iter.Value(), visiting)
+			if err != nil {
+				return nil, at(err, "["+inspectKey(key)+"]")
+			}
+
+			if !hash.Set(key, value) {
+				return nil, newConvertError("unusable as hash key: %s", key.Type())
+			}
+		}
+		return hash, nil
+	case reflect.Struct:
+		hash := NewHash()
+		for _, f := range structFields(v.Type()) {
+			value, err := fromValue(v.Field(f.index), visiting)
+			if err != nil {
+				return nil, at(err, "["+inspectKey(&String{Value: f.name})+"]")
+			}
+
+			hash.Set(&String{Value: f.name}, value)
+		}
+		return hash, nil
+	default:
+		return nil, newConvertError("unsupported type %s", v.Type())
+	}
+}
+
+// ToGo convert obj into value pointed by target.
+// target can be ints, string, bool, time.Time, slices, arrays, maps,
+// structs, pointers and interfaces. interface{} receives int64, string,
+// bool, time.Time, []interface{}, map[string]interface{} or nil.
+func ToGo(obj Object, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return newConvertError("target must be non-nil pointer, got %T", target)
+	}
+
+	return toValue(obj, v.Elem())
+}
+
+func toValue(obj Object, v reflect.Value) error {
+	t := v.Type()
+
+	if t == objectType {
+		v.Set(reflect.ValueOf(&obj).Elem())
+		return nil
+	}
+
+	if t == timeType {
+		tm, ok := obj.(*Time)
+		if !ok {
+			return mismatch(TIME_OBJ, obj)
+		}
+		v.Set(reflect.ValueOf(tm.Value))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integer, ok := obj.(*Integer)
+		if !ok {
+			return mismatch(INTEGER_OBJ, obj)
+		}
+		if v.OverflowInt(integer.Value) {
+			return newConvertError("overflows %s, got %d", t, integer.Value)
+		}
+		v.SetInt(integer.Value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		integer, ok := obj.(*Integer)
+		if !ok {
+			return mismatch(INTEGER_OBJ, obj)
+		}
+		if integer.Value < 0 || v.OverflowUint(uint64(integer.Value)) {
+			return newConvertError("overflows %s, got %d", t, integer.Value)
+		}
+		v.SetUint(uint64(integer.Value))
+	case reflect.String:
+		str, ok := obj.(*String)
+		if !ok {
+			return mismatch(STRING_OBJ, obj)
+		}
+		v.SetString(str.Value)
+	case reflect.Bool:
+		boolean, ok := obj.(*Boolean)
+		if !ok {
+			return mismatch(BOOLEAN_OBJ, obj)
+		}
+		v.SetBool(boolean.Value)
+	case reflect.Ptr:
+		if obj.Type() == NULL_OBJ {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+
+		elem := reflect.New(t.Elem())
+		if err := toValue(obj, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return newConvertError("unsupported type %s", t)
+		}
+
+		natural, err := toNatural(obj)
+		if err != nil {
+			return err
+		}
+		if natural != nil {
+			v.Set(reflect.ValueOf(natural))
+		} else {
+			v.Set(reflect.Zero(t))
+		}
+	case reflect.Slice:
+		if obj.Type() == NULL_OBJ {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+
+		arr, ok := obj.(*Array)
+		if !ok {
+			return mismatch(ARRAY_OBJ, obj)
+		}
+
+		v.Set(reflect.MakeSlice(t, len(arr.Elements), len(arr.Elements)))
+		return toElements(arr, v)
+	case reflect.Array:
+		arr, ok := obj.(*Array)
+		if !ok {
+			return mismatch(ARRAY_OBJ, obj)
+		}
+
+		if len(arr.Elements) != v.Len() {
+			return newConvertError("must have %d elements, got %d", v.Len(), len(arr.Elements))
+		}
+		return toElements(arr, v)
+	case reflect.Map:
+		if obj.Type() == NULL_OBJ {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+
+		hash, ok := obj.(*Hash)
+		if !ok {
+			return mismatch(HASH_OBJ, obj)
+		}
+
+		m := reflect.MakeMapWithSize(t, hash.Len())
+		for _, pair := range hash.SortedPairs() {
+			key := reflect.New(t.Key()).Elem()
+			if err := toValue(pair.Key, key); err != nil {
+				return at(err, "key "+inspectKey(pair.Key))
+			}
+
+			value := reflect.New(t.Elem()).Elem()
+			if err := toValue(pair.Value, value); err != nil {
+				return at(err, "["+inspectKey(pair.Key)+"]")
+			}
+
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	case reflect.Struct:
+		hash, ok := obj.(*Hash)
+		if !ok {
+			return mismatch(HASH_OBJ, obj)
+		}
+
+		// keys not matching any field are ignored
+		for _, f := range structFields(t) {
+			key := &String{Value: f.name}
+			value, ok := hash.Get(key)
+			if !ok {
+				continue
+			}
+
+			if err := toValue(value, v.Field(f.index)); err != nil {
+				return at(err, "["+inspectKey(key)+"]")
+			}
+		}
+	default:
+		return newConvertError("unsupported type %s", t)
+	}
+
+	return nil
+}
+
+func toElements(arr *Array, v reflect.Value) error {
+	for i, e := range arr.Elements {
+		if err := toValue(e, v.Index(i)); err != nil {
+			return at(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+
+	return nil
+}
+
+// toNatural convert obj to plain Go value for interface{}
+func toNatural(obj Object) (interface{}, error) {
+	switch obj := obj.(type) {
+	case *Null:
+		return nil, nil
+	case *Integer:
+		return obj.Value, nil
+	case *String:
+		return obj.Value, nil
+	case *Boolean:
+		return obj.Value, nil
+	case *Time:
+		return obj.Value, nil
+	case *Array:
+		values := make([]interface{}, len(obj.Elements), len(obj.Elements))
+		for i, e := range obj.Elements {
+			value, err := toNatural(e)
+			if err != nil {
+				return nil, at(err, fmt.Sprintf("[%d]", i))
+			}
+			values[i] = value
+		}
+		return values, nil
+	case *Hash:
+		values := make(map[string]interface{}, obj.Len())
+		for _, pair := range obj.SortedPairs() {
+			key, ok := pair.Key.(*String)
+			if !ok {
+				return nil, newConvertError("must have STRING keys for interface{}, got %s",
+					pair.Key.Type())
+			}
+
+			value, err := toNatural(pair.Value)
+			if err != nil {
+				return nil, at(err, "["+inspectKey(key)+"]")
+			}
+			values[key.Value] = value
+		}
+		return values, nil
+	default:
+		return nil, newConvertError("unsupported type %s", obj.Type())
+	}
+}
+
+// Convertible report whether values of t can be converted by FromGo and ToGo
+func Convertible(t reflect.Type) bool {
+	return convertible(t, map[reflect.Type]bool{})
+}
+
+func convertible(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t == objectType || t == timeType {
+		return true
+	}
+
+	// recursive types such as linked list
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.String, reflect.Bool:
+		return true
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return convertible(t.Elem(), seen)
+	case reflect.Map:
+		return convertible(t.Key(), seen) && convertible(t.Elem(), seen)
+	case reflect.Struct:
+		for _, f := range structFields(t) {
+			if !convertible(t.Field(f.index).Type, seen) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+type structField struct {
+	index int
+	name  string
+}
+
+// structFields return exported fields of t named by `monkey` tag
+func structFields(t reflect.Type) []structField {
+	fields := []structField{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("monkey"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fields = append(fields, structField{index: i, name: name})
+	}
+
+	return fields
+}
+
+func asObject(v reflect.Value) (Object, bool) {
+	if !v.Type().Implements(objectType) {
+		return nil, false
+	}
+
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return NULL, true
+	}
+
+	return v.Interface().(Object), true
+}
+
+func inspectKey(key Object) string {
+	if str, ok := key.(*String); ok {
+		return fmt.Sprintf("%q", str.Value)
+	}
+
+	return key.Inspect()
+}
+
+func mismatch(expected Type, obj Object) error {
+	return newConvertError("must be %s, got %s", expected, obj.Type())
+}
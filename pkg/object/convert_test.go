@@ -0,0 +1,257 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testAddress struct {
+	City string `monkey:"city"`
+}
+
+type testUser struct {
+	Name     string           `monkey:"name"`
+	Age      int              `monkey:"age"`
+	Admin    bool             `monkey:"admin"`
+	Tags     []string         `monkey:"tags"`
+	Address  *testAddress     `monkey:"address"`
+	Extra    map[string]int64 `monkey:"extra"`
+	Password string           `monkey:"-"`
+	Nickname string
+	secret   string
+}
+
+type testNode struct {
+	Value int       `monkey:"value"`
+	Next  *testNode `monkey:"next"`
+}
+
+func TestFromGo(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected string
+	}{
+		{nil, "null"},
+		{42, "42"},
+		{uint8(7), "7"},
+		{"a", "a"},
+		{true, "true"},
+		{[]int{1, 2}, "[1, 2]"},
+		{[2]string{"a", "b"}, "[a, b]"},
+		{[]interface{}{1, "a", nil}, "[1, a, null]"},
+		{map[string]int{"b": 2, "a": 1}, "{a: 1, b: 2}"},
+		{map[int]bool{2: true, 1: false}, "{1: false, 2: true}"},
+		{(*testAddress)(nil), "null"},
+		{[]int(nil), "null"},
+		{&testAddress{City: "Tokyo"}, "{city: Tokyo}"},
+		{
+			testUser{Name: "bob", Age: 30, Tags: []string{"x"}, Password: "p", Nickname: "b", secret: "s"},
+			"{Nickname: b, address: null, admin: false, age: 30, extra: null, name: bob, tags: [x]}",
+		},
+		{&Integer{Value: 3}, "3"},
+		{time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), "2020-01-02T03:04:05Z"},
+	}
+
+	for _, tt := range tests {
+		obj, err := FromGo(tt.input)
+		if err != nil {
+			t.Errorf("FromGo(%#v) returned error: %s", tt.input, err)
+			continue
+		}
+
+		if obj.Inspect() != tt.expected {
+			t.Errorf("FromGo(%#v) is not %q. got=%q", tt.input, tt.expected, obj.Inspect())
+		}
+	}
+}
+
+func TestFromGoErrors(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected string
+	}{
+		{1.5, "object: unsupported type float64"},
+		{[]interface{}{1, func() {}}, "object: [1] unsupported type func()"},
+		{map[string]chan int{"c": nil}, `object: ["c"] unsupported type chan int`},
+		{uint64(1 << 63), "object: 9223372036854775808 overflows INTEGER"},
+	}
+
+	for _, tt := range tests {
+		_, err := FromGo(tt.input)
+		if err == nil {
+			t.Errorf("FromGo(%#v) does not return error", tt.input)
+			continue
+		}
+
+		if err.Error() != tt.expected {
+			t.Errorf("error is not %q. got=%q", tt.expected, err.Error())
+		}
+	}
+}
+
+func TestFromGoCycle(t *testing.T) {
+	node := &testNode{Value: 1}
+	node.Next = &testNode{Value: 2, Next: node}
+
+	hash := map[string]interface{}{}
+	hash["self"] = hash
+
+	array := []interface{}{nil}
+	array[0] = array
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+	}{
+		{"pointer", node, `object: ["next"]["next"] cyclic value of type *object.testNode`},
+		{"map", hash, `object: ["self"] cyclic value of type map[string]interface {}`},
+		{"slice", array, `object: [0] cyclic value of type []interface {}`},
+	}
+
+	for _, tt := range tests {
+		_, err := FromGo(tt.input)
+		if err == nil {
+			t.Errorf("FromGo(%s) does not return error", tt.name)
+			continue
+		}
+
+		if err.Error() != tt.expected {
+			t.Errorf("error is not %q. got=%q", tt.expected, err.Error())
+		}
+	}
+
+	// value shared w/o cycle is converted for each reference
+	shared := &testAddress{City: "Tokyo"}
+	obj, err := FromGo([]*testAddress{shared, shared})
+	if err != nil {
+		t.Fatalf("FromGo returned error: %s", err)
+	}
+
+	if obj.Inspect() != "[{city: Tokyo}, {city: Tokyo}]" {
+		t.Errorf("shared value is not converted. got=%q", obj.Inspect())
+	}
+}
+
+func TestToGo(t *testing.T) {
+	user := testUser{Name: "bob", Age: 30, Admin: true, Tags: []string{"x", "y"},
+		Address: &testAddress{City: "Tokyo"}, Extra: map[string]int64{"score": 10}}
+
+	obj, err := FromGo(user)
+	if err != nil {
+		t.Fatalf("FromGo returned error: %s", err)
+	}
+
+	var got testUser
+	if err := ToGo(obj, &got); err != nil {
+		t.Fatalf("ToGo returned error: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, user) {
+		t.Errorf("ToGo does not round trip. got=%+v, want=%+v", got, user)
+	}
+
+	var natural interface{}
+	if err := ToGo(obj, &natural); err != nil {
+		t.Fatalf("ToGo returned error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"name": "bob", "age": int64(30), "admin": true,
+		"tags":     []interface{}{"x", "y"},
+		"address":  map[string]interface{}{"city": "Tokyo"},
+		"extra":    map[string]interface{}{"score": int64(10)},
+		"Nickname": "",
+	}
+	if !reflect.DeepEqual(natural, expected) {
+		t.Errorf("ToGo into interface{} is not %v. got=%v", expected, natural)
+	}
+
+	var ptr *int
+	if err := ToGo(NULL, &ptr); err != nil || ptr != nil {
+		t.Errorf("ToGo(NULL) into pointer is not nil. got=%v, err=%v", ptr, err)
+	}
+
+	var arr [2]int
+	if err := ToGo(&Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}}, &arr); err != nil || arr != [2]int{1, 2} {
+		t.Errorf("ToGo into array is not [1 2]. got=%v, err=%v", arr, err)
+	}
+
+	var keyed map[int]string
+	hash := NewHash()
+	hash.Set(&Integer{Value: 1}, &String{Value: "a"})
+	if err := ToGo(hash, &keyed); err != nil || keyed[1] != "a" {
+		t.Errorf("ToGo into map[int]string is not map[1:a]. got=%v, err=%v", keyed, err)
+	}
+
+	var passthrough Object
+	if err := ToGo(TRUE, &passthrough); err != nil || passthrough != TRUE {
+		t.Errorf("ToGo into Object does not pass through. got=%v, err=%v", passthrough, err)
+	}
+}
+
+func TestToGoErrors(t *testing.T) {
+	var i8 int8
+	var s string
+	var user testUser
+	var m map[int]string
+	var f float64
+	var arr [3]int
+
+	tests := []struct {
+		obj      Object
+		target   interface{}
+		expected string
+	}{
+		{&Integer{Value: 1}, s, "object: target must be non-nil pointer, got string"},
+		{&Integer{Value: 1}, &s, "object: must be STRING, got INTEGER"},
+		{&Integer{Value: 300}, &i8, "object: overflows int8, got 300"},
+		{&Integer{Value: 1}, &f, "object: unsupported type float64"},
+		{&Array{Elements: []Object{}}, &arr, "object: must have 3 elements, got 0"},
+		{newTestHash("name", &Integer{Value: 1}), &user, `object: ["name"] must be STRING, got INTEGER`},
+		{
+			newTestHash("tags", &Array{Elements: []Object{TRUE}}),
+			&user,
+			`object: ["tags"][0] must be STRING, got BOOLEAN`,
+		},
+		{newTestHash("a", &String{Value: "b"}), &m, `object: key "a" must be INTEGER, got STRING`},
+	}
+
+	for _, tt := range tests {
+		err := ToGo(tt.obj, tt.target)
+		if err == nil {
+			t.Errorf("ToGo(%s) does not return error", tt.obj.Inspect())
+			continue
+		}
+
+		if err.Error() != tt.expected {
+			t.Errorf("error is not %q. got=%q", tt.expected, err.Error())
+		}
+	}
+}
+
+func TestConvertible(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+
+	tests := []struct {
+		value    interface{}
+		expected bool
+	}{
+		{0, true},
+		{"", true},
+		{[]map[string]bool{}, true},
+		{testUser{}, true},
+		{node{}, true},
+		{map[string]float64{}, false},
+		{struct{ F func() }{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := Convertible(reflect.TypeOf(tt.value)); got != tt.expected {
+			t.Errorf("Convertible(%T) is not %t", tt.value, tt.expected)
+		}
+	}
+}
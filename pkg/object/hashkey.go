@@ -0,0 +1,99 @@
+package object
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+)
+
+// HashKeyOf return hash key of obj.
+// Arrays and hashes are hashed by their contents, Hash.Set keeps frozen copy
+// of them as key so that key of pair is not changed after it is set.
+// ok is false if obj or any of its contents is not hashable.
+func HashKeyOf(obj Object) (HashKey, bool) {
+	switch obj := obj.(type) {
+	case Hashable:
+		return obj.HashKey(), true
+	case *Array:
+		h := fnv.New64a()
+
+		for _, e := range obj.Elements {
+			key, ok := HashKeyOf(e)
+			if !ok {
+				return HashKey{}, false
+			}
+
+			writeHashKey(h, key)
+		}
+
+		return HashKey{
+			Type:  obj.Type(),
+			Value: h.Sum64(),
+		}, true
+	case *Hash:
+		// pairs have no order, so hash of each pair is summed up
+		var value uint64
+
+		for key, bucket := range obj.Pairs {
+			for _, pair := range bucket {
+				v, ok := HashKeyOf(pair.Value)
+				if !ok {
+					return HashKey{}, false
+				}
+
+				h := fnv.New64a()
+				writeHashKey(h, key)
+				writeHashKey(h, v)
+				value += h.Sum64()
+			}
+		}
+
+		return HashKey{
+			Type:  obj.Type(),
+			Value: value,
+		}, true
+	default:
+		return HashKey{}, false
+	}
+}
+
+func writeHashKey(w io.Writer, key HashKey) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], key.Value)
+
+	w.Write([]byte(key.Type))
+	w.Write(buf[:])
+}
+
+// frozenKey return copy of key which is not changed by change of key, hashes
+// in it are frozen. hashable key other than array and hash is returned as is.
+func frozenKey(key Object) Object {
+	switch key := key.(type) {
+	case *Array:
+		elements := make([]Object, len(key.Elements))
+		for i, e := range key.Elements {
+			elements[i] = frozenKey(e)
+		}
+
+		return &Array{Elements: elements}
+	case *Hash:
+		if key.frozen {
+			return key
+		}
+
+		hash := NewHash()
+		for k, bucket := range key.Pairs {
+			for _, pair := range bucket {
+				hash.Pairs[k] = append(hash.Pairs[k], HashPair{
+					Key:   frozenKey(pair.Key),
+					Value: frozenKey(pair.Value),
+				})
+			}
+		}
+		hash.frozen = true
+
+		return hash
+	default:
+		return key
+	}
+}
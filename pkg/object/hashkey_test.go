@@ -0,0 +1,63 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestHashKeyOf(t *testing.T) {
+	one := &Integer{Value: 1}
+	two := &Integer{Value: 2}
+
+	tests := []struct {
+		a        Object
+		b        Object
+		expected bool
+	}{
+		{&Array{Elements: []Object{one, two}}, &Array{Elements: []Object{one, two}}, true},
+		{&Array{Elements: []Object{one, two}}, &Array{Elements: []Object{two, one}}, false},
+		{&Array{Elements: []Object{}}, &Array{Elements: []Object{}}, true},
+		{&Array{Elements: []Object{one}}, one, false},
+		{
+			&Array{Elements: []Object{&Array{Elements: []Object{one}}, two}},
+			&Array{Elements: []Object{&Array{Elements: []Object{one}}, two}},
+			true,
+		},
+		{newTestHash("a", one), newTestHash("a", one), true},
+		{newTestHash("a", one), newTestHash("a", two), false},
+		{newTestHash("a", one), newTestHash("b", one), false},
+	}
+
+	for _, tt := range tests {
+		a, ok := HashKeyOf(tt.a)
+		if !ok {
+			t.Errorf("%s is not hashable", tt.a.Inspect())
+			continue
+		}
+
+		b, ok := HashKeyOf(tt.b)
+		if !ok {
+			t.Errorf("%s is not hashable", tt.b.Inspect())
+			continue
+		}
+
+		if (a == b) != tt.expected {
+			t.Errorf("HashKeyOf(%s) == HashKeyOf(%s) is not %t",
+				tt.a.Inspect(), tt.b.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestHashKeyOfUnhashable(t *testing.T) {
+	tests := []Object{
+		&Builtin{},
+		&Null{},
+		&Array{Elements: []Object{&Integer{Value: 1}, &Builtin{}}},
+		newTestHash("a", &Builtin{}),
+	}
+
+	for _, obj := range tests {
+		if _, ok := HashKeyOf(obj); ok {
+			t.Errorf("%s is hashable", obj.Inspect())
+		}
+	}
+}
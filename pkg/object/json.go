@@ -0,0 +1,131 @@
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// FromJSON decode single JSON value into Hash, Array, String, Integer,
+// Boolean or NULL. numbers must be integer since Monkey has no float.
+func FromJSON(data []byte) (Object, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("json: %s", err)
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("json: unexpected data after top-level value")
+	}
+
+	return fromJSONValue(v)
+}
+
+func fromJSONValue(v interface{}) (Object, error) {
+	switch v := v.(type) {
+	case nil:
+		return NULL, nil
+	case bool:
+		if v {
+			return TRUE, nil
+		}
+		return FALSE, nil
+	case string:
+		return &String{Value: v}, nil
+	case json.Number:
+		i, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("json: number %s is not integer", v)
+		}
+		return &Integer{Value: i}, nil
+	case []interface{}:
+		elements := make([]Object, len(v), len(v))
+		for i, e := range v {
+			obj, err := fromJSONValue(e)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = obj
+		}
+		return &Array{Elements: elements}, nil
+	case map[string]interface{}:
+		hash := NewHash()
+		for key, value := range v {
+			obj, err := fromJSONValue(value)
+			if err != nil {
+				return nil, err
+			}
+			hash.Set(&String{Value: key}, obj)
+		}
+		return hash, nil
+	default:
+		return nil, fmt.Errorf("json: unsupported value %T", v)
+	}
+}
+
+// ToJSON encode obj as JSON, keys of Hash are sorted and must be String.
+// output is indented by indent unless it is empty.
+func ToJSON(obj Object, indent string) ([]byte, error) {
+	v, err := toJSONValue(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("json: %s", err)
+	}
+
+	// Encode always terminates value with newline
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+func toJSONValue(obj Object) (interface{}, error) {
+	switch obj := obj.(type) {
+	case *Null:
+		return nil, nil
+	case *Boolean:
+		return obj.Value, nil
+	case *Integer:
+		return obj.Value, nil
+	case *String:
+		return obj.Value, nil
+	case *Array:
+		values := make([]interface{}, len(obj.Elements), len(obj.Elements))
+		for i, e := range obj.Elements {
+			v, err := toJSONValue(e)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	case *Hash:
+		values := map[string]interface{}{}
+		for _, pair := range obj.SortedPairs() {
+			key, ok := pair.Key.(*String)
+			if !ok {
+				return nil, fmt.Errorf("json: unsupported key type %s, keys must be STRING",
+					pair.Key.Type())
+			}
+
+			v, err := toJSONValue(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			values[key.Value] = v
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("json: unsupported type %s", obj.Type())
+	}
+}
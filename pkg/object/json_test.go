@@ -0,0 +1,129 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestFromJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`1`, "1"},
+		{`-42`, "-42"},
+		{`"a\nb"`, "a\nb"},
+		{`true`, "true"},
+		{`null`, "null"},
+		{`[1, "a", [false]]`, "[1, a, [false]]"},
+		{`{"b": 1, "a": {"c": null}}`, "{a: {c: null}, b: 1}"},
+		{`{}`, "{}"},
+	}
+
+	for _, tt := range tests {
+		obj, err := FromJSON([]byte(tt.input))
+		if err != nil {
+			t.Errorf("FromJSON(%q) returned error: %s", tt.input, err)
+			continue
+		}
+
+		if obj.Inspect() != tt.expected {
+			t.Errorf("FromJSON(%q) is not %q. got=%q", tt.input, tt.expected, obj.Inspect())
+		}
+	}
+}
+
+func TestFromJSONSingletons(t *testing.T) {
+	obj, err := FromJSON([]byte(`[null, true, false]`))
+	if err != nil {
+		t.Fatalf("FromJSON returned error: %s", err)
+	}
+
+	elements := obj.(*Array).Elements
+	if elements[0] != NULL || elements[1] != TRUE || elements[2] != FALSE {
+		t.Errorf("FromJSON does not return singletons. got=%v", elements)
+	}
+}
+
+func TestFromJSONErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`1.5`, "json: number 1.5 is not integer"},
+		{`[1e3]`, "json: number 1e3 is not integer"},
+		{`{"a": 1`, "json: unexpected EOF"},
+		{`1 2`, "json: unexpected data after top-level value"},
+		{``, "json: EOF"},
+	}
+
+	for _, tt := range tests {
+		_, err := FromJSON([]byte(tt.input))
+		if err == nil {
+			t.Errorf("FromJSON(%q) does not return error", tt.input)
+			continue
+		}
+
+		if err.Error() != tt.expected {
+			t.Errorf("FromJSON(%q) error is not %q. got=%q", tt.input, tt.expected, err)
+		}
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "b"}, &Array{Elements: []Object{&Integer{Value: 1}, NULL}})
+	hash.Set(&String{Value: "a"}, &String{Value: "<x>"})
+
+	tests := []struct {
+		obj      Object
+		indent   string
+		expected string
+	}{
+		{&Integer{Value: 1}, "", "1"},
+		{TRUE, "", "true"},
+		{NULL, "", "null"},
+		{&String{Value: "\"q\""}, "", `"\"q\""`},
+		{&Array{Elements: []Object{}}, "", "[]"},
+		{hash, "", `{"a":"<x>","b":[1,null]}`},
+		{hash, "  ", "{\n  \"a\": \"<x>\",\n  \"b\": [\n    1,\n    null\n  ]\n}"},
+	}
+
+	for _, tt := range tests {
+		data, err := ToJSON(tt.obj, tt.indent)
+		if err != nil {
+			t.Errorf("ToJSON(%s) returned error: %s", tt.obj.Inspect(), err)
+			continue
+		}
+
+		if string(data) != tt.expected {
+			t.Errorf("ToJSON(%s) is not %q. got=%q", tt.obj.Inspect(), tt.expected, data)
+		}
+	}
+}
+
+func TestToJSONErrors(t *testing.T) {
+	intKey := NewHash()
+	intKey.Set(&Integer{Value: 1}, TRUE)
+
+	tests := []struct {
+		obj      Object
+		expected string
+	}{
+		{&Builtin{}, "json: unsupported type BUILTIN"},
+		{&Array{Elements: []Object{&Builtin{}}}, "json: unsupported type BUILTIN"},
+		{intKey, "json: unsupported key type INTEGER, keys must be STRING"},
+		{newTestHash("a", &Builtin{}), "json: unsupported type BUILTIN"},
+	}
+
+	for _, tt := range tests {
+		_, err := ToJSON(tt.obj, "")
+		if err == nil {
+			t.Errorf("ToJSON(%s) does not return error", tt.obj.Inspect())
+			continue
+		}
+
+		if err.Error() != tt.expected {
+			t.Errorf("ToJSON error is not %q. got=%q", tt.expected, err)
+		}
+	}
+}
@@ -4,7 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/naoto0822/monkey-interpreter/pkg/ast"
 )
@@ -23,6 +28,33 @@ const (
 	BUILTIN_OBJ      = "BUILTIN"
 	ARRAY_OBJ        = "ARRAY"
 	HASH_OBJ         = "HASH"
+	REGEX_OBJ        = "REGEX"
+	TIME_OBJ         = "TIME"
+	TASK_OBJ         = "TASK"
+	CHANNEL_OBJ      = "CHANNEL"
+	MODULE_OBJ       = "MODULE"
+	STRUCT_TYPE_OBJ  = "STRUCT_TYPE"
+	STRUCT_OBJ       = "STRUCT"
+	QUOTE_OBJ        = "QUOTE"
+	MACRO_OBJ        = "MACRO"
+)
+
+// kind of Error
+const (
+	RUNTIME_ERROR  = "RuntimeError"
+	TYPE_ERROR     = "TypeError"
+	NAME_ERROR     = "NameError"
+	ARGUMENT_ERROR = "ArgumentError"
+	VALUE_ERROR    = "ValueError"
+	LIMIT_ERROR    = "LimitError"
+	THROWN_ERROR   = "Error"
+)
+
+// singletons of Null and Boolean, evaluator compares them by pointer
+var (
+	NULL  = &Null{}
+	TRUE  = &Boolean{Value: true}
+	FALSE = &Boolean{Value: false}
 )
 
 // Object monkey value
@@ -128,9 +160,14 @@ func (r *ReturnValue) Inspect() string {
 
 var _ Object = (*Error)(nil)
 
-// Error is error
+// Error is error, propagated until it is caught by try
 type Error struct {
 	Message string
+	Kind    string
+	// Stack is called functions, innermost first
+	Stack []string
+	// Value is thrown object, nil for runtime errors
+	Value Object
 }
 
 // Type implements Object
@@ -143,10 +180,35 @@ func (e *Error) Inspect() string {
 	return "ERROR: " + e.Message
 }
 
-// Environment has let identifier
+// Error implements error, so that host can return it as Go error
+func (e *Error) Error() string {
+	return e.Kind + ": " + e.Message
+}
+
+// Copy return copy of e w/ its own Stack, Error may be shared by tasks,
+// modules and host so that it must be copied before it is changed
+func (e *Error) Copy() *Error {
+	err := *e
+	err.Stack = append([]string(nil), e.Stack...)
+	return &err
+}
+
+// Environment has let identifier, it is safe for concurrent use.
+// frozen Environment is read without lock, so that many goroutines can
+// share it as outer of their own Environment.
 type Environment struct {
+	mu    sync.RWMutex
 	store map[string]Object
 	outer *Environment
+	// caller is where host values of Env calling function of this Env are
+	// found, they are looked up through it instead of outer
+	caller *Environment
+	// values is set by host for builtins, such as output writer
+	values map[interface{}]interface{}
+	// depth is number of nested function calls to reach Env
+	depth int
+	// frozen is 1 after Freeze
+	frozen int32
 }
 
 // NewEnvironment gen Environment
@@ -160,25 +222,141 @@ func NewEnvironment() *Environment {
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
+	if outer != nil {
+		env.depth = outer.depth
+	}
+	return env
+}
+
+// NewFunctionEnvironment gen Env of function call,
+// bindings are resolved in outer and host values in caller
+func NewFunctionEnvironment(outer, caller *Environment) *Environment {
+	env := NewEnclosedEnvironment(outer)
+	if caller != nil {
+		env.caller = caller.valueScope()
+		env.depth = caller.depth + 1
+	}
 	return env
 }
 
+// valueScope return nearest Env which has host values in lookup path of e.
+// Env w/o values are skipped, so that lookup from nested function calls
+// does not walk whole call stack and closures do not keep it alive.
+func (e *Environment) valueScope() *Environment {
+	env := e
+	for {
+		unlock := env.rlock()
+		n := len(env.values)
+		unlock()
+
+		switch {
+		case n > 0:
+			return env
+		case env.caller != nil:
+			// caller is valueScope already
+			return env.caller
+		case env.outer == nil:
+			return env
+		}
+
+		env = env.outer
+	}
+}
+
+// CallDepth return number of nested function calls to reach Env
+func (e *Environment) CallDepth() int {
+	return e.depth
+}
+
 // Get is get object
 func (e *Environment) Get(name string) (Object, bool) {
+	unlock := e.rlock()
 	obj, ok := e.store[name]
+	unlock()
+
 	if !ok && e.outer != nil {
-		obj, ok = e.outer.Get(name)
+		return e.outer.Get(name)
 	}
 
 	return obj, ok
 }
 
-// Set is set object w/ name
+// Set is set object w/ name, it panics if Env is frozen
 func (e *Environment) Set(name string, obj Object) Object {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.IsFrozen() {
+		panic("object: Set on frozen Environment: " + name)
+	}
+
 	e.store[name] = obj
 	return obj
 }
 
+// SetValue set host value of key, which is visible from enclosed Env.
+// key should be unexported type of the package using it, like context.Context.
+// it panics if Env is frozen.
+func (e *Environment) SetValue(key, value interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.IsFrozen() {
+		panic("object: SetValue on frozen Environment")
+	}
+
+	if e.values == nil {
+		e.values = make(map[interface{}]interface{})
+	}
+
+	e.values[key] = value
+}
+
+// Value return host value of key, nil if not set
+func (e *Environment) Value(key interface{}) interface{} {
+	unlock := e.rlock()
+	value, ok := e.values[key]
+	unlock()
+
+	if ok {
+		return value
+	}
+
+	if e.caller != nil {
+		return e.caller.Value(key)
+	}
+
+	if e.outer != nil {
+		return e.outer.Value(key)
+	}
+
+	return nil
+}
+
+// Freeze make Env read-only. enclosed Env of frozen Env can be used by
+// each goroutine, such as per request, while sharing bindings of it.
+func (e *Environment) Freeze() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	atomic.StoreInt32(&e.frozen, 1)
+}
+
+// rlock lock Env for reading unless it is frozen, and return unlock
+func (e *Environment) rlock() func() {
+	if e.IsFrozen() {
+		return func() {}
+	}
+
+	e.mu.RLock()
+	return e.mu.RUnlock
+}
+
+// IsFrozen report whether Freeze is called
+func (e *Environment) IsFrozen() bool {
+	return atomic.LoadInt32(&e.frozen) == 1
+}
+
 var _ Object = (*Function)(nil)
 
 // Function is fn()
@@ -229,19 +407,26 @@ func (s *String) Inspect() string {
 	return s.Value
 }
 
-// HashKey gen hash key
-func (s *String) HashKey() HashKey {
+// stringHasher hash value of String for HashKey.
+// It is replaced only by tests to force collisions, keys of existing
+// hashes are broken if it is changed while they are used.
+var stringHasher = func(value string) uint64 {
 	h := fnv.New64a()
-	h.Write([]byte(s.Value))
+	h.Write([]byte(value))
+
+	return h.Sum64()
+}
 
+// HashKey gen hash key
+func (s *String) HashKey() HashKey {
 	return HashKey{
 		Type:  s.Type(),
-		Value: h.Sum64(),
+		Value: stringHasher(s.Value),
 	}
 }
 
-// BuiltinFunction return Object
-type BuiltinFunction func(args ...Object) Object
+// BuiltinFunction return Object, env is environment of the caller
+type BuiltinFunction func(env *Environment, args ...Object) Object
 
 var _ Object = (*Builtin)(nil)
 
@@ -298,9 +483,20 @@ type HashPair struct {
 	Value Object
 }
 
-// Hash is {k:v}
+// Hash is {k:v}.
+// Pairs are bucketed by HashKey of the key, and keys in the same bucket
+// are told apart by Equal, so colliding keys never overwrite each other.
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	Pairs map[HashKey][]HashPair
+	// frozen Hash is key of other Hash, it can not be changed by Set and Delete
+	frozen bool
+}
+
+// NewHash gen empty Hash
+func NewHash() *Hash {
+	return &Hash{
+		Pairs: make(map[HashKey][]HashPair),
+	}
 }
 
 // Type implements Object
@@ -313,7 +509,7 @@ func (h *Hash) Inspect() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for _, p := range h.Pairs {
+	for _, p := range h.SortedPairs() {
 		pair := fmt.Sprintf("%s: %s", p.Key.Inspect(), p.Value.Inspect())
 		pairs = append(pairs, pair)
 	}
@@ -324,3 +520,323 @@ func (h *Hash) Inspect() string {
 
 	return out.String()
 }
+
+// Len return number of pairs
+func (h *Hash) Len() int {
+	length := 0
+	for _, bucket := range h.Pairs {
+		length += len(bucket)
+	}
+
+	return length
+}
+
+// SortedPairs return pairs in stable order.
+// Keys are ordered by type name first, then keys of the same type by Compare
+// (integers numerically, strings lexicographically, false before true),
+// and keys which can not be compared by Inspect.
+func (h *Hash) SortedPairs() []HashPair {
+	pairs := make([]HashPair, 0, h.Len())
+	for _, bucket := range h.Pairs {
+		pairs = append(pairs, bucket...)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i].Key, pairs[j].Key
+		if a.Type() != b.Type() {
+			return a.Type() < b.Type()
+		}
+
+		if result, ok := Compare(a, b); ok {
+			return result < 0
+		}
+
+		return a.Inspect() < b.Inspect()
+	})
+
+	return pairs
+}
+
+// Copy return Hash which has the same pairs, copy of frozen Hash is not frozen
+func (h *Hash) Copy() *Hash {
+	hash := NewHash()
+	for k, bucket := range h.Pairs {
+		hash.Pairs[k] = append([]HashPair{}, bucket...)
+	}
+
+	return hash
+}
+
+// Get return value of key, ok is false if key is not found or not hashable
+func (h *Hash) Get(key Object) (Object, bool) {
+	hashed, ok := HashKeyOf(key)
+	if !ok {
+		return nil, false
+	}
+
+	for _, p := range h.Pairs[hashed] {
+		if Equal(p.Key, key) {
+			return p.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Set set value of key, return false if key is not hashable.
+// array or hash key is copied, it panics if Hash is frozen.
+func (h *Hash) Set(key, value Object) bool {
+	if h.frozen {
+		panic("object: Set on frozen Hash")
+	}
+
+	hashed, ok := HashKeyOf(key)
+	if !ok {
+		return false
+	}
+
+	key = frozenKey(key)
+
+	bucket := h.Pairs[hashed]
+	for i, p := range bucket {
+		if Equal(p.Key, key) {
+			bucket[i] = HashPair{Key: key, Value: value}
+			return true
+		}
+	}
+
+	h.Pairs[hashed] = append(bucket, HashPair{Key: key, Value: value})
+	return true
+}
+
+// Delete remove key, return false if key is not found.
+// it panics if Hash is frozen.
+func (h *Hash) Delete(key Object) bool {
+	if h.frozen {
+		panic("object: Delete on frozen Hash")
+	}
+
+	hashed, ok := HashKeyOf(key)
+	if !ok {
+		return false
+	}
+
+	bucket := h.Pairs[hashed]
+	for i, p := range bucket {
+		if !Equal(p.Key, key) {
+			continue
+		}
+
+		if len(bucket) == 1 {
+			delete(h.Pairs, hashed)
+		} else {
+			h.Pairs[hashed] = append(bucket[:i:i], bucket[i+1:]...)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+var _ Object = (*Regex)(nil)
+
+// Regex is compiled regular expression
+type Regex struct {
+	Value *regexp.Regexp
+}
+
+// Type implements Object
+func (r *Regex) Type() Type {
+	return REGEX_OBJ
+}
+
+// Inspect implements Object
+func (r *Regex) Inspect() string {
+	return "/" + r.Value.String() + "/"
+}
+
+var _ Object = (*Time)(nil)
+
+// Time is point in time
+type Time struct {
+	Value time.Time
+}
+
+// Type implements Object
+func (t *Time) Type() Type {
+	return TIME_OBJ
+}
+
+// Inspect implements Object
+func (t *Time) Inspect() string {
+	return t.Value.Format(time.RFC3339Nano)
+}
+
+var _ Object = (*Task)(nil)
+
+// Task is function running concurrently, Result is set before Done is closed
+type Task struct {
+	Done   chan struct{}
+	Result Object
+}
+
+// Type implements Object
+func (t *Task) Type() Type {
+	return TASK_OBJ
+}
+
+// Inspect implements Object
+func (t *Task) Inspect() string {
+	select {
+	case <-t.Done:
+		return "task(done)"
+	default:
+		return "task(running)"
+	}
+}
+
+var _ Object = (*Channel)(nil)
+
+// Channel is Go channel of Object
+type Channel struct {
+	Value chan Object
+}
+
+// Type implements Object
+func (c *Channel) Type() Type {
+	return CHANNEL_OBJ
+}
+
+// Inspect implements Object
+func (c *Channel) Inspect() string {
+	return fmt.Sprintf("channel(%d/%d)", len(c.Value), cap(c.Value))
+}
+
+var _ Object = (*Module)(nil)
+
+// Module is imported file, Exports are bindings declared w/ export
+type Module struct {
+	Name    string
+	Path    string
+	Exports map[string]Object
+}
+
+// Type implements Object
+func (m *Module) Type() Type {
+	return MODULE_OBJ
+}
+
+// Inspect implements Object
+func (m *Module) Inspect() string {
+	return "module(" + m.Name + ")"
+}
+
+var _ Object = (*StructType)(nil)
+
+// StructType is struct Name { fields }, it is called as constructor
+type StructType struct {
+	Name   string
+	Fields []string
+}
+
+// Type implements Object
+func (s *StructType) Type() Type {
+	return STRUCT_TYPE_OBJ
+}
+
+// Inspect implements Object
+func (s *StructType) Inspect() string {
+	return "struct " + s.Name + " { " + strings.Join(s.Fields, ", ") + " }"
+}
+
+var _ Object = (*Struct)(nil)
+
+// Struct is instance of StructType, Values are in order of its Fields
+type Struct struct {
+	StructType *StructType
+	Values     []Object
+}
+
+// Type implements Object
+func (s *Struct) Type() Type {
+	return STRUCT_OBJ
+}
+
+// Inspect implements Object
+func (s *Struct) Inspect() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for i, name := range s.StructType.Fields {
+		fields = append(fields, name+": "+s.Values[i].Inspect())
+	}
+
+	out.WriteString(s.StructType.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Get return value of field name
+func (s *Struct) Get(name string) (Object, bool) {
+	for i, field := range s.StructType.Fields {
+		if field == name {
+			return s.Values[i], true
+		}
+	}
+
+	return nil, false
+}
+
+var _ Object = (*Quote)(nil)
+
+// Quote is unevaluated ast.Node returned by quote()
+type Quote struct {
+	Node ast.Node
+}
+
+// Type implements Object
+func (q *Quote) Type() Type {
+	return QUOTE_OBJ
+}
+
+// Inspect implements Object
+func (q *Quote) Inspect() string {
+	return "QUOTE(" + q.Node.String() + ")"
+}
+
+var _ Object = (*Macro)(nil)
+
+// Macro is macro(), it is called w/ quoted arguments before evaluation
+type Macro struct {
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+// Type implements Object
+func (m *Macro) Type() Type {
+	return MACRO_OBJ
+}
+
+// Inspect implements Object
+func (m *Macro) Inspect() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("macro")
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") {\n")
+	out.WriteString(m.Body.String())
+	out.WriteString("\n}")
+
+	return out.String()
+}
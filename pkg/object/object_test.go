@@ -0,0 +1,257 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestHashCollision(t *testing.T) {
+	defaultHasher := stringHasher
+	stringHasher = func(string) uint64 { return 42 }
+	defer func() { stringHasher = defaultHasher }()
+
+	a := &String{Value: "a"}
+	b := &String{Value: "b"}
+
+	if a.HashKey() != b.HashKey() {
+		t.Fatalf("hash keys do not collide")
+	}
+
+	hash := NewHash()
+	hash.Set(a, &Integer{Value: 1})
+	hash.Set(b, &Integer{Value: 2})
+
+	if hash.Len() != 2 {
+		t.Fatalf("hash.Len() is not 2. got=%d", hash.Len())
+	}
+
+	testHashValue(t, hash, "a", 1)
+	testHashValue(t, hash, "b", 2)
+
+	hash.Set(&String{Value: "a"}, &Integer{Value: 3})
+	if hash.Len() != 2 {
+		t.Fatalf("hash.Len() is not 2. got=%d", hash.Len())
+	}
+
+	testHashValue(t, hash, "a", 3)
+	testHashValue(t, hash, "b", 2)
+
+	if !hash.Delete(&String{Value: "a"}) {
+		t.Fatalf("hash.Delete(a) is false")
+	}
+
+	if _, ok := hash.Get(a); ok {
+		t.Errorf("a is not deleted")
+	}
+
+	testHashValue(t, hash, "b", 2)
+
+	if hash.Delete(&String{Value: "c"}) {
+		t.Errorf("hash.Delete(c) is true")
+	}
+
+	// equality does not depend on order of colliding keys
+	other := NewHash()
+	other.Set(&String{Value: "c"}, &Integer{Value: 3})
+	other.Set(b, &Integer{Value: 2})
+	hash.Set(&String{Value: "c"}, &Integer{Value: 3})
+
+	if !hash.Equal(other) {
+		t.Errorf("hash is not equal to other w/ same pairs")
+	}
+
+	other.Set(b, &Integer{Value: 4})
+	if hash.Equal(other) {
+		t.Errorf("hash is equal to other w/ different value")
+	}
+}
+
+func TestHashUnhashableKey(t *testing.T) {
+	hash := NewHash()
+
+	if hash.Set(&Builtin{}, &Integer{Value: 1}) {
+		t.Errorf("hash.Set with BUILTIN key is true")
+	}
+
+	if _, ok := hash.Get(&Builtin{}); ok {
+		t.Errorf("hash.Get with BUILTIN key is true")
+	}
+
+	if hash.Len() != 0 {
+		t.Errorf("hash.Len() is not 0. got=%d", hash.Len())
+	}
+}
+
+func testHashValue(t *testing.T, hash *Hash, key string, expected int64) {
+	value, ok := hash.Get(&String{Value: key})
+	if !ok {
+		t.Errorf("no value for %s", key)
+		return
+	}
+
+	integer, ok := value.(*Integer)
+	if !ok || integer.Value != expected {
+		t.Errorf("value of %s is not %d. got=%s", key, expected, value.Inspect())
+	}
+}
+
+func TestHashCompositeKeyIsFrozen(t *testing.T) {
+	array := &Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}}
+	inner := NewHash()
+	inner.Set(&String{Value: "a"}, &Integer{Value: 1})
+
+	hash := NewHash()
+	hash.Set(array, &Integer{Value: 10})
+	hash.Set(inner, &Integer{Value: 20})
+
+	// changing keys after Set does not break pairs
+	array.Elements[0] = &Integer{Value: 9}
+	inner.Set(&String{Value: "a"}, &Integer{Value: 2})
+
+	key := &Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}}
+	if value, ok := hash.Get(key); !ok || value.Inspect() != "10" {
+		t.Errorf("value of [1, 2] is not 10. got=%v", value)
+	}
+
+	innerKey := NewHash()
+	innerKey.Set(&String{Value: "a"}, &Integer{Value: 1})
+	if value, ok := hash.Get(innerKey); !ok || value.Inspect() != "20" {
+		t.Errorf("value of {a: 1} is not 20. got=%v", value)
+	}
+
+	var frozen *Hash
+	for _, pair := range hash.SortedPairs() {
+		if h, ok := pair.Key.(*Hash); ok {
+			frozen = h
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Set on frozen hash does not panic")
+			}
+		}()
+		frozen.Set(&String{Value: "a"}, &Integer{Value: 3})
+	}()
+
+	copied := frozen.Copy()
+	copied.Set(&String{Value: "a"}, &Integer{Value: 3})
+	testHashValue(t, copied, "a", 3)
+	testHashValue(t, frozen, "a", 1)
+}
+
+func TestHashSortedPairs(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "b"}, &Integer{Value: 1})
+	hash.Set(&Integer{Value: 10}, &Integer{Value: 2})
+	hash.Set(&String{Value: "a"}, &Integer{Value: 3})
+	hash.Set(&Integer{Value: 2}, &Integer{Value: 4})
+	hash.Set(&Boolean{Value: true}, &Integer{Value: 5})
+
+	expected := []string{"true", "2", "10", "a", "b"}
+
+	pairs := hash.SortedPairs()
+	if len(pairs) != len(expected) {
+		t.Fatalf("pairs does not contain %d. got=%d", len(expected), len(pairs))
+	}
+
+	for i, key := range expected {
+		if pairs[i].Key.Inspect() != key {
+			t.Errorf("pairs[%d].Key is not %s. got=%s", i, key, pairs[i].Key.Inspect())
+		}
+	}
+}
+
+func TestHashCopy(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "a"}, &Integer{Value: 1})
+
+	copied := hash.Copy()
+	copied.Set(&String{Value: "a"}, &Integer{Value: 2})
+	copied.Set(&String{Value: "b"}, &Integer{Value: 3})
+
+	testHashValue(t, hash, "a", 1)
+	testHashValue(t, copied, "a", 2)
+
+	if hash.Len() != 1 {
+		t.Errorf("hash.Len() is not 1. got=%d", hash.Len())
+	}
+}
+
+func TestEnvironmentFreeze(t *testing.T) {
+	base := NewEnvironment()
+	base.Set("a", &Integer{Value: 1})
+	base.SetValue("key", "base")
+	base.Freeze()
+
+	if !base.IsFrozen() {
+		t.Fatalf("base is not frozen")
+	}
+
+	child := NewEnclosedEnvironment(base)
+	child.Set("b", &Integer{Value: 2})
+
+	if obj, ok := child.Get("a"); !ok || obj.Inspect() != "1" {
+		t.Errorf("child does not see binding of base. got=%v", obj)
+	}
+	if _, ok := base.Get("b"); ok {
+		t.Errorf("binding of child leaks into base")
+	}
+	if child.Value("key") != "base" {
+		t.Errorf("child does not see value of base. got=%v", child.Value("key"))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Set on frozen Environment does not panic")
+		}
+	}()
+	base.Set("c", &Integer{Value: 3})
+}
+
+func TestFunctionEnvironmentValue(t *testing.T) {
+	definition := NewEnvironment()
+	definition.SetValue("key", "definition")
+
+	caller := NewEnvironment()
+	caller.SetValue("key", "caller")
+	caller.Set("x", &Integer{Value: 1})
+
+	env := NewFunctionEnvironment(definition, caller)
+	if env.Value("key") != "caller" {
+		t.Errorf("value is not looked up through caller. got=%v", env.Value("key"))
+	}
+	if _, ok := env.Get("x"); ok {
+		t.Errorf("binding is looked up through caller")
+	}
+}
+
+func TestFunctionEnvironmentSkipsCallStack(t *testing.T) {
+	host := NewEnvironment()
+	host.SetValue("key", "host")
+
+	definition := NewEnvironment()
+	env := NewFunctionEnvironment(definition, NewEnclosedEnvironment(host))
+	for i := 0; i < 1000; i++ {
+		env = NewFunctionEnvironment(definition, NewEnclosedEnvironment(env))
+	}
+
+	if env.caller != host {
+		t.Errorf("caller is not Env having values. got=%p, want=%p", env.caller, host)
+	}
+	if env.Value("key") != "host" {
+		t.Errorf("value is not looked up through caller. got=%v", env.Value("key"))
+	}
+	if env.CallDepth() != 1001 {
+		t.Errorf("env.CallDepth() is not 1001. got=%d", env.CallDepth())
+	}
+
+	// Env having its own values, such as module, is kept in the path
+	module := NewFunctionEnvironment(nil, env)
+	module.SetValue("key", "module")
+
+	env = NewFunctionEnvironment(definition, module)
+	if env.Value("key") != "module" {
+		t.Errorf("value is not looked up through module. got=%v", env.Value("key"))
+	}
+}
@@ -0,0 +1,455 @@
+package optimizer
+
+import (
+	"strconv"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/ast"
+	"github.com/naoto0822/monkey-interpreter/pkg/token"
+)
+
+// Optimize fold constant expressions, eliminate dead if branches and inline
+// immutable top-level let constants. program is rewritten in place.
+func Optimize(program *ast.Program) *ast.Program {
+	o := &optimizer{
+		constants: make(map[string]ast.Expression),
+		bindings:  make(map[string]int),
+		inline:    true,
+	}
+	o.countBindings(program)
+
+	program.Statements = o.optimizeStatements(program.Statements, true, true)
+	return program
+}
+
+// Fold is Optimize w/o inlining let constants, for program evaluated in
+// globals which may be rebound later by other program or host.
+func Fold(program *ast.Program) *ast.Program {
+	o := &optimizer{
+		constants: make(map[string]ast.Expression),
+	}
+
+	program.Statements = o.optimizeStatements(program.Statements, true, true)
+	return program
+}
+
+type optimizer struct {
+	// constants is top-level let name and its literal value
+	constants map[string]ast.Expression
+	// bindings is how many times a name is bound by let or parameter
+	bindings map[string]int
+	// inline is whether constants are inlined
+	inline bool
+}
+
+// optimizeStatements optimize stmts. last is whether the value of the final
+// statement is used, topLevel is whether stmts run in the program scope.
+func (o *optimizer) optimizeStatements(stmts []ast.Statement, last, topLevel bool) []ast.Statement {
+	result := []ast.Statement{}
+
+	for i, stmt := range stmts {
+		isLast := last && i == len(stmts)-1
+		result = append(result, o.optimizeStatement(stmt, isLast, topLevel)...)
+	}
+
+	return result
+}
+
+func (o *optimizer) optimizeStatement(stmt ast.Statement, last, topLevel bool) []ast.Statement {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		stmt.Value = o.optimizeExpression(stmt.Value)
+
+		if o.inline && topLevel && isConstant(stmt.Value) && o.bindings[stmt.Name.Value] == 1 {
+			o.constants[stmt.Name.Value] = stmt.Value
+		}
+	case *ast.ReturnStatement:
+		stmt.ReturnValue = o.optimizeExpression(stmt.ReturnValue)
+	case *ast.ThrowStatement:
+		stmt.Value = o.optimizeExpression(stmt.Value)
+	case *ast.ExportStatement:
+		o.optimizeStatement(stmt.Statement, last, topLevel)
+	case *ast.ExpressionStatement:
+		stmt.Expression = o.optimizeExpression(stmt.Expression)
+
+		ifExp, ok := stmt.Expression.(*ast.IfExpression)
+		if !ok || !isConstant(ifExp.Condition) {
+			break
+		}
+
+		// blocks share the enclosing environment,
+		// so the taken branch can be spliced into the statement list.
+		taken := ifExp.Alternative
+		if isTruthy(ifExp.Condition) {
+			taken = ifExp.Consequence
+		}
+
+		if taken != nil && len(taken.Statements) > 0 {
+			return taken.Statements
+		}
+
+		if !last {
+			return []ast.Statement{}
+		}
+	case *ast.BlockStatement:
+		stmt.Statements = o.optimizeStatements(stmt.Statements, last, topLevel)
+	}
+
+	return []ast.Statement{stmt}
+}
+
+func (o *optimizer) optimizeBlock(block *ast.BlockStatement) *ast.BlockStatement {
+	if block == nil {
+		return nil
+	}
+
+	block.Statements = o.optimizeStatements(block.Statements, true, false)
+	return block
+}
+
+func (o *optimizer) optimizeExpression(exp ast.Expression) ast.Expression {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		if value, ok := o.constants[exp.Value]; ok {
+			return copyConstant(value)
+		}
+	case *ast.PrefixExpression:
+		exp.Right = o.optimizeExpression(exp.Right)
+		if folded := foldPrefix(exp); folded != nil {
+			return folded
+		}
+	case *ast.InfixExpression:
+		exp.Left = o.optimizeExpression(exp.Left)
+		exp.Right = o.optimizeExpression(exp.Right)
+		if folded := foldInfix(exp); folded != nil {
+			return folded
+		}
+	case *ast.IfExpression:
+		return o.optimizeIfExpression(exp)
+	case *ast.FunctionLiteral:
+		exp.Body = o.optimizeBlock(exp.Body)
+	case *ast.CallExpression:
+		// quoted code is kept as it is written
+		if ident, ok := exp.Function.(*ast.Identifier); ok && ident.Value == "quote" {
+			break
+		}
+
+		exp.Function = o.optimizeExpression(exp.Function)
+		for i, a := range exp.Arguments {
+			exp.Arguments[i] = o.optimizeExpression(a)
+		}
+	case *ast.ArrayLiteral:
+		for i, e := range exp.Elements {
+			exp.Elements[i] = o.optimizeExpression(e)
+		}
+	case *ast.IndexExpression:
+		exp.Left = o.optimizeExpression(exp.Left)
+		exp.Index = o.optimizeExpression(exp.Index)
+	case *ast.DotExpression:
+		exp.Left = o.optimizeExpression(exp.Left)
+	case *ast.HashLiteral:
+		pairs := make(map[ast.Expression]ast.Expression)
+		for k, v := range exp.Pairs {
+			pairs[o.optimizeExpression(k)] = o.optimizeExpression(v)
+		}
+		exp.Pairs = pairs
+	case *ast.TryExpression:
+		exp.Block = o.optimizeBlock(exp.Block)
+		exp.Catch = o.optimizeBlock(exp.Catch)
+		exp.Finally = o.optimizeBlock(exp.Finally)
+	}
+
+	return exp
+}
+
+func (o *optimizer) optimizeIfExpression(exp *ast.IfExpression) ast.Expression {
+	exp.Condition = o.optimizeExpression(exp.Condition)
+	exp.Consequence = o.optimizeBlock(exp.Consequence)
+	exp.Alternative = o.optimizeBlock(exp.Alternative)
+
+	if !isConstant(exp.Condition) {
+		return exp
+	}
+
+	if isTruthy(exp.Condition) {
+		exp.Alternative = nil
+		if e, ok := singleExpression(exp.Consequence); ok {
+			return e
+		}
+
+		return exp
+	}
+
+	if exp.Alternative == nil {
+		return exp
+	}
+
+	if e, ok := singleExpression(exp.Alternative); ok {
+		return e
+	}
+
+	exp.Consequence = &ast.BlockStatement{
+		Token:      exp.Consequence.Token,
+		Statements: []ast.Statement{},
+	}
+
+	return exp
+}
+
+func (o *optimizer) countBindings(node ast.Node) {
+	switch node := node.(type) {
+	case *ast.Program:
+		for _, s := range node.Statements {
+			o.countBindings(s)
+		}
+	case *ast.LetStatement:
+		o.bindings[node.Name.Value]++
+		o.countBindings(node.Value)
+	case *ast.ReturnStatement:
+		o.countBindings(node.ReturnValue)
+	case *ast.ThrowStatement:
+		o.countBindings(node.Value)
+	case *ast.ImportStatement:
+		o.bindings[node.Name()]++
+	case *ast.StructStatement:
+		o.bindings[node.Name.Value]++
+	case *ast.ExportStatement:
+		o.countBindings(node.Statement)
+	case *ast.ExpressionStatement:
+		o.countBindings(node.Expression)
+	case *ast.BlockStatement:
+		for _, s := range node.Statements {
+			o.countBindings(s)
+		}
+	case *ast.PrefixExpression:
+		o.countBindings(node.Right)
+	case *ast.InfixExpression:
+		o.countBindings(node.Left)
+		o.countBindings(node.Right)
+	case *ast.IfExpression:
+		o.countBindings(node.Condition)
+		o.countBindings(node.Consequence)
+		if node.Alternative != nil {
+			o.countBindings(node.Alternative)
+		}
+	case *ast.FunctionLiteral:
+		for _, p := range node.Parameters {
+			o.bindings[p.Value]++
+		}
+		o.countBindings(node.Body)
+	case *ast.CallExpression:
+		o.countBindings(node.Function)
+		for _, a := range node.Arguments {
+			o.countBindings(a)
+		}
+	case *ast.ArrayLiteral:
+		for _, e := range node.Elements {
+			o.countBindings(e)
+		}
+	case *ast.IndexExpression:
+		o.countBindings(node.Left)
+		o.countBindings(node.Index)
+	case *ast.DotExpression:
+		o.countBindings(node.Left)
+	case *ast.HashLiteral:
+		for k, v := range node.Pairs {
+			o.countBindings(k)
+			o.countBindings(v)
+		}
+	case *ast.TryExpression:
+		o.countBindings(node.Block)
+		if node.Catch != nil {
+			o.bindings[node.Param.Value]++
+			o.countBindings(node.Catch)
+		}
+		if node.Finally != nil {
+			o.countBindings(node.Finally)
+		}
+	}
+}
+
+// foldPrefix return folded literal or nil,
+// results follow evalPrefixExpression.
+func foldPrefix(exp *ast.PrefixExpression) ast.Expression {
+	switch exp.Operator {
+	case "!":
+		switch right := exp.Right.(type) {
+		case *ast.Boolean:
+			return newBoolean(!right.Value)
+		case *ast.IntegerLiteral, *ast.StringLiteral:
+			return newBoolean(false)
+		}
+	case "-":
+		if right, ok := exp.Right.(*ast.IntegerLiteral); ok {
+			return newInteger(-right.Value)
+		}
+	}
+
+	return nil
+}
+
+// foldInfix return folded literal or nil,
+// results follow evalInfixExpression.
+func foldInfix(exp *ast.InfixExpression) ast.Expression {
+	switch left := exp.Left.(type) {
+	case *ast.IntegerLiteral:
+		right, ok := exp.Right.(*ast.IntegerLiteral)
+		if !ok {
+			return nil
+		}
+
+		return foldIntegerInfix(exp.Operator, left.Value, right.Value)
+	case *ast.StringLiteral:
+		right, ok := exp.Right.(*ast.StringLiteral)
+		if !ok {
+			return nil
+		}
+
+		return foldStringInfix(exp.Operator, left.Value, right.Value)
+	case *ast.Boolean:
+		right, ok := exp.Right.(*ast.Boolean)
+		if !ok {
+			return nil
+		}
+
+		switch exp.Operator {
+		case "==":
+			return newBoolean(left.Value == right.Value)
+		case "!=":
+			return newBoolean(left.Value != right.Value)
+		}
+	}
+
+	return nil
+}
+
+func foldIntegerInfix(operator string, left, right int64) ast.Expression {
+	switch operator {
+	case "+":
+		return newInteger(left + right)
+	case "-":
+		return newInteger(left - right)
+	case "*":
+		return newInteger(left * right)
+	case "/":
+		// division by zero is left to the evaluator
+		if right == 0 {
+			return nil
+		}
+		return newInteger(left / right)
+	case "%":
+		if right == 0 {
+			return nil
+		}
+		return newInteger(left % right)
+	case "<":
+		return newBoolean(left < right)
+	case ">":
+		return newBoolean(left > right)
+	case "==":
+		return newBoolean(left == right)
+	case "!=":
+		return newBoolean(left != right)
+	}
+
+	return nil
+}
+
+func foldStringInfix(operator string, left, right string) ast.Expression {
+	switch operator {
+	case "+":
+		return newString(left + right)
+	case "<":
+		return newBoolean(left < right)
+	case ">":
+		return newBoolean(left > right)
+	case "==":
+		return newBoolean(left == right)
+	case "!=":
+		return newBoolean(left != right)
+	}
+
+	return nil
+}
+
+func isConstant(exp ast.Expression) bool {
+	switch exp.(type) {
+	case *ast.IntegerLiteral, *ast.StringLiteral, *ast.Boolean:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTruthy follow isTruthly of evaluator for constant exp
+func isTruthy(exp ast.Expression) bool {
+	if b, ok := exp.(*ast.Boolean); ok {
+		return b.Value
+	}
+
+	return true
+}
+
+func singleExpression(block *ast.BlockStatement) (ast.Expression, bool) {
+	if block == nil || len(block.Statements) != 1 {
+		return nil, false
+	}
+
+	stmt, ok := block.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		return nil, false
+	}
+
+	return stmt.Expression, true
+}
+
+func copyConstant(exp ast.Expression) ast.Expression {
+	switch exp := exp.(type) {
+	case *ast.IntegerLiteral:
+		return newInteger(exp.Value)
+	case *ast.StringLiteral:
+		return newString(exp.Value)
+	case *ast.Boolean:
+		return newBoolean(exp.Value)
+	default:
+		return exp
+	}
+}
+
+func newInteger(value int64) *ast.IntegerLiteral {
+	return &ast.IntegerLiteral{
+		Token: token.Token{
+			Type:    token.INT,
+			Literal: strconv.FormatInt(value, 10),
+		},
+		Value: value,
+	}
+}
+
+func newString(value string) *ast.StringLiteral {
+	return &ast.StringLiteral{
+		Token: token.Token{
+			Type:    token.STRING,
+			Literal: value,
+		},
+		Value: value,
+	}
+}
+
+func newBoolean(value bool) *ast.Boolean {
+	tok := token.Token{
+		Type:    token.FALSE,
+		Literal: "false",
+	}
+
+	if value {
+		tok = token.Token{
+			Type:    token.TRUE,
+			Literal: "true",
+		}
+	}
+
+	return &ast.Boolean{
+		Token: tok,
+		Value: value,
+	}
+}
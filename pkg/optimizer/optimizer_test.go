@@ -0,0 +1,183 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/naoto0822/monkey-interpreter/pkg/ast"
+	"github.com/naoto0822/monkey-interpreter/pkg/evaluator"
+	"github.com/naoto0822/monkey-interpreter/pkg/lexer"
+	"github.com/naoto0822/monkey-interpreter/pkg/object"
+	"github.com/naoto0822/monkey-interpreter/pkg/parser"
+)
+
+func TestConstantFolding(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"60 * 60 * 24", "86400"},
+		{"1 + 2 * 3", "7"},
+		{"-(5 + 5)", "-10"},
+		{"10 / 3", "3"},
+		{"10 / 0", "(10 / 0)"},
+		{"10 % 3", "1"},
+		{"10 % 0", "(10 % 0)"},
+		{"1 < 2", "true"},
+		{"1 == 2", "false"},
+		{"!true", "false"},
+		{"!5", "false"},
+		{"true != false", "true"},
+		{`"a" + "b" + "c"`, "abc"},
+		{`"a" < "b"`, "true"},
+		{`"a" == "a"`, "true"},
+		{`"a" - "b"`, "(a - b)"},
+		{"x + 2 * 3", "(x + 6)"},
+		{"fn(x) { x * (2 + 3) }", "fn(x) (x * 5)"},
+		{"add(1 + 1, 2 * 2)", "add(2, 4)"},
+		{"[1 + 1, 2]", "[2, 2]"},
+		{"a[1 + 1]", "(a[2])"},
+		{"[1 + 1].map(f)", "([2].map)(f)"},
+		{"quote(1 + 1)", "quote((1 + 1))"},
+	}
+
+	for _, tt := range tests {
+		program := testOptimize(t, tt.input)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() is not %q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestDeadBranchElimination(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (false) { 10 }; 5", "5"},
+		{"if (false) { 10 }", "iffalse 10"},
+		{"if (false) { 10 } else { 20 }", "20"},
+		{"if (1 < 2) { 10 } else { 20 }", "10"},
+		{"if (true) { let a = x; a }; 5", "let a = x;a5"},
+		{"let y = if (false) { 1 } else { 2 };", "let y = 2;"},
+		{"if (x) { 1 + 1 } else { 2 }", "ifx 2else 2"},
+		{"fn() { if (true) { return 1; } 2 }", "fn() return 1;2"},
+	}
+
+	for _, tt := range tests {
+		program := testOptimize(t, tt.input)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() is not %q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestConstantInlining(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = 2; let b = a * 3; b", "let a = 2;let b = 6;6"},
+		{`let s = "mon" + "key"; s`, "let s = monkey;monkey"},
+		{"a; let a = 1; a", "alet a = 1;1"},
+		{"let a = 1; let a = 2; a", "let a = 1;let a = 2;a"},
+		{"let a = 1; fn(a) { a }", "let a = 1;fn(a) a"},
+		{"let a = 1; let f = fn() { let a = 2; a }; a", "let a = 1;let f = fn() let a = 2;a;a"},
+		{"let a = 1; let f = fn() { a + 1 }", "let a = 1;let f = fn() 2;"},
+		{"let a = x; a", "let a = x;a"},
+		{"let e = 1; try { e } catch (e) { e }", "let e = 1;try ecatch(e) e"},
+		{`let m = "boom"; throw m + "!";`, "let m = boom;throw boom!;"},
+		{"export let a = 1 + 1; a", "export let a = 2;2"},
+		{`let lib = 1; import "x/lib"; lib`, `let lib = 1;import "x/lib";lib`},
+		{`let l = 1; import "lib" as l; l`, `let l = 1;import "lib" as l;l`},
+		{"let P = 1; struct P { x }; P", "let P = 1;struct P { x }P"},
+	}
+
+	for _, tt := range tests {
+		program := testOptimize(t, tt.input)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() is not %q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = 2; let b = a * 3; b", "let a = 2;let b = (a * 3);b"},
+		{"let a = 1; let f = fn() { a + 1 + 1 }", "let a = 1;let f = fn() ((a + 1) + 1);"},
+		{"let a = 60 * 60; if (1 < 2) { a }", "let a = 3600;a"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) > 0 {
+			t.Fatalf("parser has errors: %v", p.Errors())
+		}
+
+		if folded := Fold(program); folded.String() != tt.expected {
+			t.Errorf("program.String() is not %q. got=%q", tt.expected, folded.String())
+		}
+	}
+}
+
+func TestOptimizeKeepsResult(t *testing.T) {
+	tests := []string{
+		"let day = 60 * 60 * 24; let f = fn(x) { x * day }; f(2)",
+		"if (false) { 10 }",
+		"if (true) { let a = 1; }",
+		"let a = 5; if (a > 2) { a * 2 } else { 0 }",
+		`let greet = fn(name) { "hello " + name }; greet("mon" + "key")`,
+		"let f = fn() { if (true) { return 1; } 2 }; f()",
+		"5 + true",
+	}
+
+	for _, input := range tests {
+		expected := testEval(t, input, false)
+		obj := testEval(t, input, true)
+
+		if expected == nil || obj == nil {
+			if expected != obj {
+				t.Errorf("%q: result is not %v. got=%v", input, expected, obj)
+			}
+			continue
+		}
+
+		if obj.Inspect() != expected.Inspect() {
+			t.Errorf("%q: result is not %s. got=%s", input, expected.Inspect(), obj.Inspect())
+		}
+	}
+}
+
+func testOptimize(t *testing.T, input string) *ast.Program {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser has errors: %v", p.Errors())
+	}
+
+	return Optimize(program)
+}
+
+func testEval(t *testing.T, input string, optimize bool) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser has errors: %v", p.Errors())
+	}
+
+	if optimize {
+		program = Optimize(program)
+	}
+
+	return evaluator.Eval(program, object.NewEnvironment())
+}
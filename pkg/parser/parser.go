@@ -18,7 +18,7 @@ const (
 	PRODUCT     // *
 	PREFIX      // -X or +X
 	CALL        // myFunction()
-	INDEX       // a[1]
+	INDEX       // a[1] or a.b
 )
 
 var (
@@ -31,8 +31,10 @@ var (
 		token.MINUS:    SUM,
 		token.SLASH:    PRODUCT,
 		token.ASTERISK: PRODUCT,
+		token.PERCENT:  PRODUCT,
 		token.LPAREN:   CALL,
 		token.LBRACKET: INDEX,
+		token.DOT:      INDEX,
 	}
 )
 
@@ -71,21 +73,25 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
 
 	p.infixParseFns = make(map[token.Type]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOTEQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseDotExpression)
 
 	// to set curToken and peekToken
 	p.nextToken()
@@ -146,6 +152,14 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
+	case token.STRUCT:
+		return p.parseStructStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -195,6 +209,133 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{
+		Token: p.curToken,
+	}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseImportStatement() ast.Statement {
+	stmt := &ast.ImportStatement{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+
+	stmt.Path = p.curToken.Literal
+
+	// as is not keyword, so that it can be used as identifier
+	if p.peekTokenIs(token.IDENT) && p.peekToken.Literal == "as" {
+		p.nextToken()
+
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		stmt.Alias = &ast.Identifier{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		}
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseExportStatement() ast.Statement {
+	stmt := &ast.ExportStatement{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.LET) {
+		return nil
+	}
+
+	stmt.Statement = p.parseLetStatement()
+	if stmt.Statement == nil {
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseStructStatement() ast.Statement {
+	stmt := &ast.StructStatement{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{
+		Token: p.curToken,
+		Value: p.curToken.Literal,
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Fields = p.parseStructFields(stmt.Name.Value)
+	if stmt.Fields == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseStructFields parse field names until }, trailing comma is allowed
+func (p *Parser) parseStructFields(name string) []*ast.Identifier {
+	fields := []*ast.Identifier{}
+	seen := make(map[string]bool)
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		if seen[p.curToken.Literal] {
+			msg := fmt.Sprintf("duplicate field %s in struct %s", p.curToken.Literal, name)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		seen[p.curToken.Literal] = true
+
+		fields = append(fields, &ast.Identifier{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		})
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+
+	return fields
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{
 		Token: p.curToken,
@@ -351,6 +492,64 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return ifExp
 }
 
+func (p *Parser) parseTryExpression() ast.Expression {
+	tryExp := &ast.TryExpression{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	tryExp.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		tryExp.Param = &ast.Identifier{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		}
+
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		tryExp.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		tryExp.Finally = p.parseBlockStatement()
+	}
+
+	if tryExp.Catch == nil && tryExp.Finally == nil {
+		msg := fmt.Sprintf("expected %s or %s after try block, got %s instead",
+			token.CATCH, token.FINALLY, p.peekToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	return tryExp
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{
 		Token: p.curToken,
@@ -361,6 +560,10 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 
 	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
 		stmt := p.parseStatement()
+		if _, ok := stmt.(*ast.ExportStatement); ok {
+			p.errors = append(p.errors, "export is only allowed at top level")
+		}
+
 		if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
@@ -390,6 +593,25 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	exp := &ast.MacroLiteral{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	exp.Parameters = p.parseFunctionParameters()
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	exp.Body = p.parseBlockStatement()
+	return exp
+}
+
 func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 	params := []*ast.Identifier{}
 
@@ -483,6 +705,24 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return index
 }
 
+func (p *Parser) parseDotExpression(left ast.Expression) ast.Expression {
+	dot := &ast.DotExpression{
+		Token: p.curToken,
+		Left:  left,
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	dot.Name = &ast.Identifier{
+		Token: p.curToken,
+		Value: p.curToken.Literal,
+	}
+
+	return dot
+}
+
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{
 		Token: p.curToken,
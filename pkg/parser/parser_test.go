@@ -170,6 +170,7 @@ func TestParseInfixExpression(t *testing.T) {
 		{"5 - 5;", 5, "-", 5},
 		{"5 * 5;", 5, "*", 5},
 		{"5 / 5;", 5, "/", 5},
+		{"5 % 5;", 5, "%", 5},
 		{"5 > 5;", 5, ">", 5},
 		{"5 < 5;", 5, "<", 5},
 		{"5 == 5;", 5, "==", 5},
@@ -235,6 +236,10 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"a + b / c",
 			"(a + (b / c))",
 		},
+		{
+			"a + b % c * d",
+			"(a + ((b % c) * d))",
+		},
 		{
 			"a + b * c + d / e - f",
 			"(((a + (b * c)) + (d / e)) - f)",
@@ -303,6 +308,14 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"a * [1, 2, 3, 4][b * c] * d",
 			"((a * ([1, 2, 3, 4][(b * c)])) * d)",
 		},
+		{
+			"-a.b + c.d.e",
+			"((-(a.b)) + ((c.d).e))",
+		},
+		{
+			"a.map(f)[0].len()",
+			"(((a.map)(f)[0]).len)()",
+		},
 	}
 
 	for _, tt := range tests {
@@ -687,3 +700,338 @@ func TestParseHashLiteralStringKey(t *testing.T) {
 		testIntegerLiteral(t, v, expectedValue)
 	}
 }
+
+func TestParseThrowStatement(t *testing.T) {
+	input := `throw "boom";`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParseErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ThrowStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if stmt.TokenLiteral() != "throw" {
+		t.Errorf("stmt.TokenLiteral not throw. got=%s", stmt.TokenLiteral())
+	}
+
+	literal, ok := stmt.Value.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("stmt.Value is not ast.StringLiteral. got=%T", stmt.Value)
+	}
+
+	if literal.Value != "boom" {
+		t.Errorf("literal.Value is not boom. got=%s", literal.Value)
+	}
+}
+
+func TestParseTryExpression(t *testing.T) {
+	input := `try { x } catch (e) { e } finally { y }`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParseErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	tryExp, ok := stmt.Expression.(*ast.TryExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.TryExpression. got=%T", stmt.Expression)
+	}
+
+	if tryExp.Block.String() != "x" {
+		t.Errorf("tryExp.Block is not x. got=%s", tryExp.Block.String())
+	}
+
+	if !testIdentifier(t, tryExp.Param, "e") {
+		return
+	}
+
+	if tryExp.Catch.String() != "e" {
+		t.Errorf("tryExp.Catch is not e. got=%s", tryExp.Catch.String())
+	}
+
+	if tryExp.Finally.String() != "y" {
+		t.Errorf("tryExp.Finally is not y. got=%s", tryExp.Finally.String())
+	}
+}
+
+func TestParseTryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { x }", "expected catch or finally after try block, got EOF instead"},
+		{"try { x } catch { y }", "expected next token to be (, got { instead"},
+		{"try { x } catch (1) { y }", "expected next token to be IDENT, got INT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%q: parser has no errors", tt.input)
+			continue
+		}
+
+		if p.Errors()[0] != tt.expected {
+			t.Errorf("%q: error is not %q. got=%q", tt.input, tt.expected, p.Errors()[0])
+		}
+	}
+}
+
+func TestParseImportStatement(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedPath  string
+		expectedName  string
+		expectedAlias bool
+	}{
+		{`import "lib/math";`, "lib/math", "math", false},
+		{`import "lib/math.mk" as m`, "lib/math.mk", "m", true},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParseErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1. got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ImportStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ImportStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if stmt.Path != tt.expectedPath {
+			t.Errorf("stmt.Path is not %s. got=%s", tt.expectedPath, stmt.Path)
+		}
+
+		if stmt.Name() != tt.expectedName {
+			t.Errorf("stmt.Name() is not %s. got=%s", tt.expectedName, stmt.Name())
+		}
+
+		if (stmt.Alias != nil) != tt.expectedAlias {
+			t.Errorf("stmt.Alias is not expected. got=%v", stmt.Alias)
+		}
+	}
+}
+
+func TestParseExportStatement(t *testing.T) {
+	input := `export let x = 5;`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParseErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExportStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExportStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if !testLetStatement(t, stmt.Statement, "x") {
+		return
+	}
+
+	if stmt.String() != "export let x = 5;" {
+		t.Errorf("stmt.String() is not export let x = 5;. got=%s", stmt.String())
+	}
+}
+
+func TestParseModuleErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import lib`, "expected next token to be STRING, got IDENT instead"},
+		{`import "lib" as 1`, "expected next token to be IDENT, got INT instead"},
+		{`export x`, "expected next token to be LET, got IDENT instead"},
+		{`if (true) { export let x = 1; }`, "export is only allowed at top level"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%q: parser has no errors", tt.input)
+			continue
+		}
+
+		if p.Errors()[0] != tt.expected {
+			t.Errorf("%q: error is not %q. got=%q", tt.input, tt.expected, p.Errors()[0])
+		}
+	}
+}
+
+func TestParseDotExpression(t *testing.T) {
+	input := `point.x`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParseErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	dot, ok := stmt.Expression.(*ast.DotExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.DotExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, dot.Left, "point") {
+		return
+	}
+
+	if !testIdentifier(t, dot.Name, "x") {
+		return
+	}
+
+	l = lexer.New(`point.1`)
+	p = New(l)
+	p.ParseProgram()
+
+	expected := "expected next token to be IDENT, got INT instead"
+	if len(p.Errors()) == 0 || p.Errors()[0] != expected {
+		t.Errorf("error is not %q. got=%q", expected, p.Errors())
+	}
+}
+
+func TestParseStructStatement(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedName   string
+		expectedFields []string
+	}{
+		{`struct Point { x, y }`, "Point", []string{"x", "y"}},
+		{`struct Point { x, y, };`, "Point", []string{"x", "y"}},
+		{`struct Empty {}`, "Empty", []string{}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParseErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1. got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.StructStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.StructStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if !testIdentifier(t, stmt.Name, tt.expectedName) {
+			continue
+		}
+
+		if len(stmt.Fields) != len(tt.expectedFields) {
+			t.Fatalf("stmt.Fields does not contain %d. got=%d",
+				len(tt.expectedFields), len(stmt.Fields))
+		}
+
+		for i, f := range tt.expectedFields {
+			testIdentifier(t, stmt.Fields[i], f)
+		}
+	}
+}
+
+func TestParseStructStatementErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct { x }`, "expected next token to be IDENT, got { instead"},
+		{`struct Point x`, "expected next token to be {, got IDENT instead"},
+		{`struct Point { x y }`, "expected next token to be ,, got IDENT instead"},
+		{`struct Point { x, x }`, "duplicate field x in struct Point"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%q: parser has no errors", tt.input)
+			continue
+		}
+
+		if p.Errors()[0] != tt.expected {
+			t.Errorf("%q: error is not %q. got=%q", tt.input, tt.expected, p.Errors()[0])
+		}
+	}
+}
+
+func TestParseMacroLiteral(t *testing.T) {
+	input := `macro(x, y) { x + y; }`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParseErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	macro, ok := stmt.Expression.(*ast.MacroLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MacroLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("macro.Parameters does not contain 2. got=%d", len(macro.Parameters))
+	}
+
+	testLiteralExpression(t, macro.Parameters[0], "x")
+	testLiteralExpression(t, macro.Parameters[1], "y")
+
+	if macro.Body.String() != "(x + y)" {
+		t.Errorf("macro.Body is not (x + y). got=%s", macro.Body.String())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/naoto0822/monkey-interpreter/pkg/evaluator"
 	"github.com/naoto0822/monkey-interpreter/pkg/lexer"
@@ -30,18 +31,22 @@ const MONKEY_FACE = `            __,__
 
 // Start is starting repl
 func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
+	// reader is shared with read_line builtin
+	reader := bufio.NewReader(in)
 	env := object.NewEnvironment()
+	evaluator.SetOutput(env, out)
+	evaluator.SetInput(env, reader)
+	evaluator.SetImportPath(env, ".")
 
 	for {
 		fmt.Printf(PROMPT)
 
-		scanned := scanner.Scan()
-		if !scanned {
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
 			return
 		}
 
-		line := scanner.Text()
+		line = strings.TrimRight(line, "\r\n")
 		l := lexer.New(line)
 		p := parser.New(l)
 		program := p.ParseProgram()
@@ -51,7 +56,18 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
-		evaluated := evaluator.Eval(program, env)
+		if err := evaluator.DefineMacros(program, env); err != nil {
+			io.WriteString(out, err.Error()+"\n")
+			continue
+		}
+
+		expanded, err := evaluator.ExpandMacros(program, env)
+		if err != nil {
+			io.WriteString(out, err.Error()+"\n")
+			continue
+		}
+
+		evaluated := evaluator.Eval(expanded, env)
 
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
@@ -30,6 +30,8 @@ const (
 	ASTERISK = "*"
 	// SLASH is /
 	SLASH = "/"
+	// PERCENT is %
+	PERCENT = "%"
 	// LT is <
 	LT = "<"
 	// GT is >
@@ -43,6 +45,8 @@ const (
 
 	// COMMA is ,
 	COMMA = ","
+	// DOT is .
+	DOT = "."
 	// SEMICOLON is ;
 	SEMICOLON = ";"
 	// LPAREN is (
@@ -76,6 +80,22 @@ const (
 	ELSE = "else"
 	// RETURN is return
 	RETURN = "return"
+	// TRY is try
+	TRY = "try"
+	// CATCH is catch
+	CATCH = "catch"
+	// FINALLY is finally
+	FINALLY = "finally"
+	// THROW is throw
+	THROW = "throw"
+	// IMPORT is import
+	IMPORT = "import"
+	// EXPORT is export
+	EXPORT = "export"
+	// STRUCT is struct
+	STRUCT = "struct"
+	// MACRO is macro()
+	MACRO = "MACRO"
 )
 
 // Token is single token
@@ -85,13 +105,21 @@ type Token struct {
 }
 
 var keywords = map[string]Type{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"true":    TRUE,
+	"false":   FALSE,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETURN,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"throw":   THROW,
+	"import":  IMPORT,
+	"export":  EXPORT,
+	"struct":  STRUCT,
+	"macro":   MACRO,
 }
 
 // LookupIdent return keyword or ident